@@ -81,6 +81,13 @@ if [ $? -ne 0 ]; then
 fi
 echo "Schema pushed to database successfully"
 
+# Full-text index on room names; db push can't express expression indexes, so (re)create it here.
+# Building it indexes all existing rows; Postgres keeps it in sync on insert/update.
+echo "Ensuring room name full-text index..."
+if ! PGPASSWORD=$POSTGRES_PASSWORD psql -h $DB_HOST -U $POSTGRES_USER -p $DB_PORT -d $POSTGRES_DB -c "CREATE INDEX IF NOT EXISTS \"Room_name_fts_idx\" ON \"Room\" USING GIN (to_tsvector('simple', \"name\"));"; then
+  echo "Failed to create room name full-text index, searches will fall back to substring matching"
+fi
+
 # Start the application
 echo "Starting the application..."
 exec node --trace-warnings dist/index.js 
\ No newline at end of file
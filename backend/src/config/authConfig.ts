@@ -0,0 +1,31 @@
+import dotenv from 'dotenv';
+import { parseDuration } from '../utils/duration';
+import { envDurationMs } from '../utils/env';
+
+// Load environment variables
+dotenv.config();
+
+// Parse a TTL setting into whole seconds, refusing zero, negative or malformed values
+const parseTtlSeconds = (name: string, fallback: string): number => {
+  const raw = process.env[name] || fallback;
+  const ms = parseDuration(raw);
+
+  if (ms === null || ms < 1000) {
+    throw new Error(`Invalid ${name} "${raw}": expected a positive duration such as 15m, 1h or 7d`);
+  }
+
+  return Math.floor(ms / 1000);
+};
+
+export const authConfig = {
+  // Lifetime of admin access tokens issued after passkey or OIDC login
+  tokenTtlSeconds: parseTtlSeconds('TOKEN_TTL', '7d'),
+  // Lifetime of refresh tokens used to obtain new access tokens
+  refreshTtlSeconds: parseTtlSeconds('REFRESH_TTL', '30d'),
+  // Delay before answering the first failed login from an IP; doubles with each further failure, 0 disables
+  loginFailureDelayMs: envDurationMs('LOGIN_FAILURE_DELAY', '250ms', { allowZero: true }),
+  // Upper bound for the failed-login delay
+  loginFailureDelayMaxMs: envDurationMs('LOGIN_FAILURE_DELAY_MAX', '5s', { allowZero: true }),
+  // How long a replica trusts the database's answer to "is this session revoked?", 0 asks on every request
+  revocationCacheTtlMs: envDurationMs('SESSION_REVOCATION_CACHE_TTL', '5s', { allowZero: true }),
+};
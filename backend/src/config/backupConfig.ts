@@ -0,0 +1,26 @@
+import dotenv from 'dotenv';
+import path from 'path';
+
+// Load environment variables
+dotenv.config();
+
+const parseIntervalMinutes = (value: string | undefined): number => {
+  if (!value || value.trim() === '') {
+    return 0;
+  }
+
+  const parsed = Number(value.trim());
+  if (!Number.isFinite(parsed) || parsed < 0) {
+    console.warn(`Invalid BACKUP_INTERVAL_MINUTES "${value}". Scheduled backups are disabled.`);
+    return 0;
+  }
+
+  return parsed;
+};
+
+export const backupConfig = {
+  // Directory where pg_dump snapshots are written
+  directory: process.env.BACKUP_DIR || path.join(__dirname, '../../backups'),
+  // Interval for scheduled backups, 0 disables the scheduler
+  intervalMinutes: parseIntervalMinutes(process.env.BACKUP_INTERVAL_MINUTES),
+};
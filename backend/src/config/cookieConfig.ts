@@ -0,0 +1,68 @@
+import dotenv from 'dotenv';
+
+// Load environment variables
+dotenv.config();
+
+type SameSite = 'Lax' | 'Strict' | 'None';
+
+interface CookieSettings {
+  sameSite: SameSite;
+  // undefined means "auto": Secure whenever the app is served over https
+  secure?: boolean;
+  domain?: string;
+  path: string;
+}
+
+const SAME_SITE_VALUES: Record<string, SameSite> = {
+  lax: 'Lax',
+  strict: 'Strict',
+  none: 'None',
+};
+
+const parseSameSite = (value: string | undefined): SameSite => {
+  if (!value || value.trim() === '') {
+    return 'Lax';
+  }
+  const sameSite = SAME_SITE_VALUES[value.trim().toLowerCase()];
+  if (!sameSite) {
+    console.warn(`Invalid COOKIE_SAMESITE "${value}", falling back to lax.`);
+    return 'Lax';
+  }
+  return sameSite;
+};
+
+const parseSecure = (value: string | undefined): boolean | undefined => {
+  if (!value || value.trim() === '' || value.trim() === 'auto') {
+    return undefined;
+  }
+  return value.trim() === 'true';
+};
+
+export const cookieConfig: CookieSettings = {
+  // SameSite attribute of the session cookie: lax, strict or none
+  sameSite: parseSameSite(process.env.COOKIE_SAMESITE),
+  // true, false or auto (Secure when BASE_URL is https)
+  secure: parseSecure(process.env.COOKIE_SECURE),
+  domain: process.env.COOKIE_DOMAIN || undefined,
+  path: process.env.COOKIE_PATH || '/',
+};
+
+/**
+ * Attributes for the OIDC session cookie. HttpOnly is always set so scripts can't read
+ * the session, and SameSite=None forces Secure since browsers drop it otherwise.
+ */
+export const buildSessionCookie = (baseURL: string, settings: CookieSettings = cookieConfig) => {
+  let secure = settings.secure ?? baseURL.startsWith('https:');
+  if (settings.sameSite === 'None' && !secure) {
+    console.warn('COOKIE_SAMESITE=none requires a Secure cookie; setting Secure.');
+    secure = true;
+  }
+
+  return {
+    httpOnly: true,
+    secure,
+    sameSite: settings.sameSite,
+    path: settings.path,
+    ...(settings.domain && { domain: settings.domain }),
+  };
+};
@@ -0,0 +1,38 @@
+import dotenv from 'dotenv';
+import { envPositiveInt } from '../utils/env';
+
+// Load environment variables
+dotenv.config();
+
+const DEFAULT_ALLOWED_ORIGINS = [
+  'http://localhost:8000',
+  'https://upload.colourstream.colourbyrogers.co.uk',
+];
+
+// Browsers send the bare origin (scheme, host, port), so reduce each entry to that form
+const parseOrigins = (value: string | undefined): string[] => {
+  const origins: string[] = [];
+  for (const entry of (value || '').split(',').map(part => part.trim()).filter(Boolean)) {
+    try {
+      origins.push(new URL(entry).origin);
+    } catch (error) {
+      console.warn(`Ignoring invalid CORS_ALLOWED_ORIGINS entry "${entry}", expected an origin such as https://example.com.`);
+    }
+  }
+  return origins;
+};
+
+export const loadCorsConfig = () => ({
+  // How long (in seconds) browsers may cache preflight results.
+  // The cors middleware only sends Access-Control-Max-Age on OPTIONS preflight responses.
+  maxAge: envPositiveInt('CORS_MAX_AGE', 600, { allowZero: true }),
+  // Origins allowed to call the API from a browser and to open the room event WebSocket.
+  // FRONTEND_URL (or the live frontend) and the built-in origins are always included.
+  allowedOrigins: Array.from(new Set([
+    process.env.FRONTEND_URL || 'https://live.colourstream.colourbyrogers.co.uk',
+    ...DEFAULT_ALLOWED_ORIGINS,
+    ...parseOrigins(process.env.CORS_ALLOWED_ORIGINS),
+  ])),
+});
+
+export const corsConfig = loadCorsConfig();
@@ -0,0 +1,20 @@
+import dotenv from 'dotenv';
+import { envDurationMs, envPositiveInt } from '../utils/env';
+
+// Load environment variables
+dotenv.config();
+
+export const loadDbConfig = () => ({
+  // Consecutive database failures that open the circuit breaker
+  breakerFailureThreshold: envPositiveInt('DB_BREAKER_FAILURE_THRESHOLD', 5),
+  // Failures only count towards the threshold if they happen within this window
+  breakerWindowMs: envDurationMs('DB_BREAKER_WINDOW', '30s'),
+  // How long the breaker stays open before letting a trial request through
+  breakerCooldownMs: envDurationMs('DB_BREAKER_COOLDOWN', '30s'),
+  // Queries slower than this are logged as warnings and counted, 0 disables
+  slowQueryThresholdMs: envDurationMs('DB_SLOW_QUERY_THRESHOLD', '200ms', { allowZero: true }),
+  // Optional read replica for read-only room queries; unset sends every query to DATABASE_URL
+  readUrl: process.env.DATABASE_READ_URL || undefined,
+});
+
+export const dbConfig = loadDbConfig();
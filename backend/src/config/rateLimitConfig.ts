@@ -0,0 +1,55 @@
+import dotenv from 'dotenv';
+import net from 'net';
+import { envPositiveInt } from '../utils/env';
+
+// Load environment variables
+dotenv.config();
+
+type RateLimitKeyStrategy = 'ip' | 'user';
+
+const parseKeyStrategy = (value: string | undefined): RateLimitKeyStrategy => {
+  if (!value || value.trim() === '') {
+    return 'ip';
+  }
+
+  const strategy = value.trim().toLowerCase();
+  if (strategy !== 'ip' && strategy !== 'user') {
+    console.warn(`Invalid RATE_LIMIT_KEY_STRATEGY "${value}", falling back to ip.`);
+    return 'ip';
+  }
+  return strategy;
+};
+
+// Parse a comma-separated list of CIDR ranges (a bare address counts as a single host)
+const parseCidrs = (name: string): string[] => {
+  const value = process.env[name] || '';
+  const cidrs: string[] = [];
+
+  for (const entry of value.split(',').map(part => part.trim()).filter(Boolean)) {
+    const [address, prefix] = entry.split('/');
+    const family = net.isIP(address);
+    const maxPrefix = family === 6 ? 128 : 32;
+    const prefixValid = prefix === undefined || (/^\d+$/.test(prefix) && Number(prefix) <= maxPrefix);
+    if (family === 0 || !prefixValid) {
+      console.warn(`Ignoring invalid ${name} entry "${entry}", expected a CIDR range such as 10.0.0.0/8.`);
+      continue;
+    }
+    cidrs.push(prefix === undefined ? `${address}/${maxPrefix}` : entry);
+  }
+
+  return cidrs;
+};
+
+export const loadRateLimitConfig = () => ({
+  // How authenticated requests are bucketed: "ip" (everyone behind an address shares a bucket)
+  // or "user" (each token's user gets its own bucket). Anonymous requests are always per IP.
+  keyStrategy: parseKeyStrategy(process.env.RATE_LIMIT_KEY_STRATEGY),
+  // Requests per 15 minutes for each IP bucket
+  ipMax: envPositiveInt('RATE_LIMIT_IP_MAX', 300),
+  // Requests per 15 minutes for each user bucket (only used with the "user" strategy)
+  userMax: envPositiveInt('RATE_LIMIT_USER_MAX', 600),
+  // CIDR ranges whose clients skip the login limiter (empty: everyone is limited)
+  loginBypassCidrs: parseCidrs('RATE_LIMIT_LOGIN_BYPASS_CIDRS'),
+});
+
+export const rateLimitConfig = loadRateLimitConfig();
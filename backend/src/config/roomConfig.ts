@@ -0,0 +1,88 @@
+import dotenv from 'dotenv';
+import { envDurationMs, envPositiveInt } from '../utils/env';
+
+// Load environment variables
+dotenv.config();
+
+const parseNameList = (value: string | undefined, fallback: string[]): string[] => {
+  if (value === undefined) {
+    return fallback;
+  }
+  return value.split(',').map(name => name.trim()).filter(Boolean);
+};
+
+// Parse "codec=h264|vp8,resolution=720p|1080p" into the values each settings key may take
+const parseSettingsAllowlist = (value: string | undefined): Record<string, string[]> => {
+  const allowlist: Record<string, string[]> = {};
+  for (const entry of (value || '').split(',').map(part => part.trim()).filter(Boolean)) {
+    const separator = entry.indexOf('=');
+    const key = entry.slice(0, separator).trim();
+    const values = entry.slice(separator + 1).split('|').map(option => option.trim()).filter(Boolean);
+    if (separator === -1 || !key || values.length === 0) {
+      console.warn(`Invalid ROOM_SETTINGS_ALLOWLIST entry "${entry}", expected key=value|value. Ignoring it.`);
+      continue;
+    }
+    allowlist[key] = values;
+  }
+  return allowlist;
+};
+
+type PageSizeOverflow = 'clamp' | 'reject';
+
+const parsePageSizeOverflow = (value: string | undefined): PageSizeOverflow => {
+  if (!value || value.trim() === '') {
+    return 'reject';
+  }
+
+  const mode = value.trim().toLowerCase();
+  if (mode !== 'clamp' && mode !== 'reject') {
+    console.warn(`Invalid ROOM_PAGE_SIZE_OVERFLOW "${value}", falling back to reject.`);
+    return 'reject';
+  }
+  return mode;
+};
+
+export const roomConfig = {
+  // Maximum join/validation attempts per second against a single room, across all clients
+  joinRateLimitPerSecond: envPositiveInt('ROOM_JOIN_RATE_LIMIT', 20),
+  // Maximum number of ids accepted by a batch lookup (GET /rooms?ids=...)
+  maxBatchIds: envPositiveInt('ROOM_BATCH_MAX_IDS', 100),
+  // Most rooms POST /rooms/bulk-create may create in one request
+  bulkCreateMax: envPositiveInt('ROOM_BULK_CREATE_MAX', 100),
+  // Most rooms POST /rooms/reconcile?apply=true may delete without an explicit confirmDeletes count
+  reconcileConfirmDeletes: envPositiveInt('ROOM_RECONCILE_CONFIRM_DELETES', 10),
+  // Page size for keyset-paginated room lists when no limit is given
+  defaultPageSize: envPositiveInt('ROOM_PAGE_SIZE', 50),
+  // Largest limit accepted by a paginated room list
+  maxPageSize: envPositiveInt('ROOM_MAX_PAGE_SIZE', 200),
+  // What a limit above maxPageSize does: "reject" answers 400, "clamp" serves maxPageSize rooms
+  pageSizeOverflow: parsePageSizeOverflow(process.env.ROOM_PAGE_SIZE_OVERFLOW),
+  // Number of rooms in the GET /rooms/feed.atom feed when no limit is given
+  feedSize: envPositiveInt('ROOM_FEED_SIZE', 20),
+  // Maximum (and default) number of results from GET /rooms/search
+  searchResultLimit: envPositiveInt('ROOM_SEARCH_LIMIT', 10),
+  // Route GET /rooms?q= through the Postgres full-text index instead of a substring scan
+  fullTextSearch: process.env.ROOM_SEARCH_FTS === 'true',
+  // Results scoring below this similarity (0-1) are left out of search results
+  searchMinScore: 0.4,
+  // Shortest name accepted when a room is created or renamed
+  minNameLength: envPositiveInt('ROOM_NAME_MIN_LENGTH', 2),
+  // Names that can't be used for rooms, compared case-insensitively
+  reservedNames: parseNameList(process.env.ROOM_RESERVED_NAMES, ['admin', 'system', 'lobby']),
+  // Settings keys GET /rooms/grouped?by= may bucket rooms by
+  groupByKeys: parseNameList(process.env.ROOM_GROUP_BY_KEYS, ['region', 'event']),
+  // Lifetime of a share link (POST /rooms/:id/share-link) when the request doesn't name one
+  shareLinkTtlMs: envDurationMs('SHARE_LINK_TTL', '24h'),
+  // Longest lifetime a share link may be given; links never outlive their room either
+  shareLinkMaxTtlMs: envDurationMs('SHARE_LINK_MAX_TTL', '30d'),
+  // Join URL encoded by GET /rooms/:id/qrcode, with {id} and {mirotalkRoomId} filled in; empty uses the room's link
+  joinUrlTemplate: process.env.ROOM_JOIN_URL || '',
+  // Media server endpoint listing the values each settings key may take; when set it replaces settingsAllowlist
+  settingsCapabilitiesUrl: process.env.ROOM_SETTINGS_CAPABILITIES_URL || '',
+  // How long the capabilities fetched from settingsCapabilitiesUrl are reused before asking again
+  settingsCapabilitiesTtlMs: envDurationMs('ROOM_SETTINGS_CAPABILITIES_TTL', '5m'),
+  // Values each settings key may take when there is no capabilities endpoint; keys not listed are not checked
+  settingsAllowlist: parseSettingsAllowlist(process.env.ROOM_SETTINGS_ALLOWLIST),
+  // Template for rooms created without a name; supports {date}, {rand} and {seq}
+  nameTemplate: process.env.ROOM_NAME_TEMPLATE || 'room-{date}-{rand}',
+};
@@ -0,0 +1,77 @@
+import dotenv from 'dotenv';
+import fs from 'fs';
+import path from 'path';
+
+// Load environment variables
+dotenv.config();
+
+/**
+ * Source of credentials such as ADMIN_AUTH_SECRET or JWT_KEY.
+ * Code reads secrets through this interface rather than process.env,
+ * so the backing store can change (e.g. to Vault) without touching callers.
+ */
+interface SecretProvider {
+  readonly name: string;
+  get(key: string): string | undefined;
+}
+
+// Reads secrets from environment variables (the historical behaviour)
+class EnvSecretProvider implements SecretProvider {
+  readonly name = 'env';
+
+  get(key: string): string | undefined {
+    return process.env[key] || undefined;
+  }
+}
+
+// Reads each secret from a file named after it, e.g. /run/secrets/JWT_KEY (Docker/Kubernetes secrets)
+class FileSecretProvider implements SecretProvider {
+  readonly name = 'file';
+  private cache = new Map<string, string | undefined>();
+
+  constructor(private readonly directory: string) {}
+
+  get(key: string): string | undefined {
+    if (!this.cache.has(key)) {
+      this.cache.set(key, this.read(key));
+    }
+    return this.cache.get(key);
+  }
+
+  private read(key: string): string | undefined {
+    // Secret names are env-style identifiers; never let one point outside the directory
+    if (!/^[A-Z0-9_]+$/.test(key)) {
+      return undefined;
+    }
+    try {
+      const value = fs.readFileSync(path.join(this.directory, key), 'utf8').replace(/\r?\n$/, '');
+      return value || undefined;
+    } catch (error) {
+      return undefined;
+    }
+  }
+}
+
+const createSecretProvider = (): SecretProvider => {
+  const provider = (process.env.SECRETS_PROVIDER || 'env').trim().toLowerCase();
+
+  switch (provider) {
+    case 'env':
+      return new EnvSecretProvider();
+    case 'file':
+      return new FileSecretProvider(process.env.SECRETS_DIR || '/run/secrets');
+    default:
+      throw new Error(`Invalid SECRETS_PROVIDER "${provider}": expected env or file`);
+  }
+};
+
+export const secrets: SecretProvider = createSecretProvider();
+
+// Read a secret the server can't run without; a missing one stops startup rather than falling back to a default
+export const requireSecret = (key: string): string => {
+  const value = secrets.get(key);
+  if (!value) {
+    throw new Error(`${key} is not set (SECRETS_PROVIDER=${secrets.name})`);
+  }
+  return value;
+};
@@ -0,0 +1,148 @@
+import dotenv from 'dotenv';
+import { parseDuration } from '../utils/duration';
+import { envDurationMs, envPositiveInt } from '../utils/env';
+
+// Load environment variables
+dotenv.config();
+
+const DEFAULT_PORT = 5001;
+
+// Fail fast on a PORT that listen() would reject or misread, naming the bad value
+const parsePort = (value: string | undefined): number => {
+  if (value === undefined || value.trim() === '') {
+    return DEFAULT_PORT;
+  }
+
+  const trimmed = value.trim();
+  const port = Number(trimmed);
+  if (!/^\d+$/.test(trimmed) || port < 1 || port > 65535) {
+    throw new Error(`Invalid PORT "${value}": expected an integer between 1 and 65535`);
+  }
+  return port;
+};
+
+// API routes are mounted under BASE_PATH, so path defaults that name them are built from it
+const apiBasePath = (): string => process.env.BASE_PATH || '/api';
+
+// Streaming endpoints that legitimately run for minutes; overridable via ROUTE_TIMEOUTS
+const defaultRouteTimeouts = (): string => `${apiBasePath()}/upload=0,/files=0,${apiBasePath()}/admin/backup=0`;
+
+interface RouteTimeout {
+  prefix: string;
+  timeoutMs: number;
+}
+
+// "0" disables the timeout; anything else must be a duration such as 500ms, 10s or 2m
+const parseTimeoutMs = (value: string): number | null => {
+  return value.trim() === '0' ? 0 : parseDuration(value);
+};
+
+// Parse "prefix=duration" pairs; entries in ROUTE_TIMEOUTS override the defaults for the same prefix
+const parseRouteTimeouts = (value: string | undefined): RouteTimeout[] => {
+  const timeouts = new Map<string, number>();
+
+  for (const source of [defaultRouteTimeouts(), value || '']) {
+    for (const entry of source.split(',').map(part => part.trim()).filter(Boolean)) {
+      const [prefix, duration] = entry.split('=').map(part => part.trim());
+      const ms = prefix && duration ? parseTimeoutMs(duration) : null;
+      if (ms === null) {
+        console.warn(`Ignoring invalid ROUTE_TIMEOUTS entry "${entry}", expected prefix=duration.`);
+        continue;
+      }
+      timeouts.set(prefix, ms);
+    }
+  }
+
+  // Longest prefix first so the most specific route wins
+  return Array.from(timeouts, ([prefix, timeoutMs]) => ({ prefix, timeoutMs }))
+    .sort((a, b) => b.prefix.length - a.prefix.length);
+};
+
+type TrailingSlashMode = 'strip' | 'redirect' | 'off';
+
+const parseTrailingSlashMode = (value: string | undefined): TrailingSlashMode => {
+  if (!value || value.trim() === '') {
+    return 'strip';
+  }
+
+  const mode = value.trim().toLowerCase();
+  if (mode !== 'strip' && mode !== 'redirect' && mode !== 'off') {
+    console.warn(`Invalid TRAILING_SLASH "${value}", falling back to strip.`);
+    return 'strip';
+  }
+  return mode;
+};
+
+// Probe and metrics paths
+const defaultAccessLogExclude = (): string => `/healthz,/livez,/readyz,/metrics,${apiBasePath()}/health`;
+
+// ACCESS_LOG_EXCLUDE replaces the defaults; an empty value logs every path
+const parseAccessLogExclude = (value: string | undefined): string[] => {
+  const source = value === undefined ? defaultAccessLogExclude() : value;
+  const paths: string[] = [];
+
+  for (const entry of source.split(',').map(part => part.trim()).filter(Boolean)) {
+    if (!entry.startsWith('/')) {
+      console.warn(`Ignoring invalid ACCESS_LOG_EXCLUDE entry "${entry}", expected a path such as /healthz.`);
+      continue;
+    }
+    paths.push(entry.length > 1 ? entry.replace(/\/+$/, '') : entry);
+  }
+  return paths;
+};
+
+const parseForceHttpsMode = (value: string | undefined): 'redirect' | 'reject' => {
+  if (!value || value.trim() === '') {
+    return 'redirect';
+  }
+
+  const mode = value.trim().toLowerCase();
+  if (mode !== 'redirect' && mode !== 'reject') {
+    console.warn(`Invalid FORCE_HTTPS_MODE "${value}", falling back to redirect.`);
+    return 'redirect';
+  }
+  return mode;
+};
+
+export const loadServerConfig = () => ({
+  // Port the HTTP server listens on
+  port: parsePort(process.env.PORT),
+  // How long in-flight requests may keep draining after SIGTERM/SIGINT
+  shutdownGracePeriodMs: envDurationMs('SHUTDOWN_GRACE_PERIOD', '10s'),
+  // Run the room create/list/get/delete smoke check after the server starts listening
+  selfTest: process.env.SELF_TEST === 'true',
+  // In-flight requests allowed before new ones get a 503 with Retry-After
+  maxConcurrentRequests: envPositiveInt('MAX_CONCURRENT_REQUESTS', 1000),
+  // Open WebSocket connections (room events, OBS status) allowed before upgrades get a 503
+  maxWebSocketClients: envPositiveInt('WS_MAX_CLIENTS', 500),
+  // Entries each in-memory cache (upload progress, Telegram message ids) keeps before evicting the least recently used
+  cacheMaxEntries: envPositiveInt('CACHE_MAX_ENTRIES', 10000),
+  // How long an in-memory cache entry lives after it was last written
+  cacheTtlMs: envDurationMs('CACHE_TTL', '24h'),
+  // Upper bound of the random extra delay added to Retry-After on 429/503, 0 disables
+  retryAfterJitterMs: envDurationMs('RETRY_AFTER_JITTER', '5s', { allowZero: true }),
+  // Answer browser /favicon.ico requests with 204 instead of a 404
+  faviconNoContent: process.env.FAVICON_NO_CONTENT !== 'false',
+  // Refuse requests that did not arrive over HTTPS at the proxy (X-Forwarded-Proto)
+  forceHttps: process.env.FORCE_HTTPS === 'true',
+  // How forceHttps refuses them: "redirect" (308 to the https URL) or "reject" (400)
+  forceHttpsMode: parseForceHttpsMode(process.env.FORCE_HTTPS_MODE),
+  // Log one line per request (method, path, status, duration)
+  accessLog: process.env.ACCESS_LOG !== 'false',
+  // Paths (and everything below them) left out of the access log
+  accessLogExcludePaths: parseAccessLogExclude(process.env.ACCESS_LOG_EXCLUDE),
+  // Permanently refuse create/update/delete requests with a 403 (public demo instances); reads keep working
+  readOnly: process.env.READ_ONLY === 'true',
+  // Answer OPTIONS (other than CORS preflights) with 204 and an Allow header listing the route's methods
+  optionsAllow: process.env.OPTIONS_ALLOW !== 'false',
+  // Indent every JSON response (development aid); ?pretty=true does it for a single request
+  prettyJson: process.env.PRETTY_JSON === 'true',
+  // "/path/" handling: "strip" serves it as "/path", "redirect" sends 301/308 to "/path", "off" leaves it alone
+  trailingSlash: parseTrailingSlashMode(process.env.TRAILING_SLASH),
+  // Default time a request may take before it is answered with a 503, 0 disables
+  requestTimeoutMs: envDurationMs('REQUEST_TIMEOUT', '30s', { allowZero: true }),
+  // Per-route overrides of requestTimeoutMs, matched by URL prefix
+  routeTimeouts: parseRouteTimeouts(process.env.ROUTE_TIMEOUTS),
+});
+
+export const serverConfig = loadServerConfig();
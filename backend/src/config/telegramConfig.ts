@@ -1,11 +1,12 @@
 import dotenv from 'dotenv';
+import { secrets } from './secrets';
 
 // Load environment variables
 dotenv.config();
 
 export const telegramConfig = {
   enabled: process.env.TELEGRAM_ENABLED === 'true',
-  botToken: process.env.TELEGRAM_BOT_TOKEN || '',
+  botToken: secrets.get('TELEGRAM_BOT_TOKEN') || '',
   chatId: process.env.TELEGRAM_CHAT_ID || '',
   sendStartupMessage: process.env.TELEGRAM_SEND_STARTUP_MESSAGE !== 'false', // Default to true if not specified
 };
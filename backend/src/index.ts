@@ -10,9 +10,12 @@ import obsRoutes from './routes/obs';
 import omenRoutes from './routes/omen';
 import securityRoutes from './routes/security';
 import healthRoutes from './routes/health';
+import timeRoutes from './routes/time';
+import joinRoutes from './routes/join';
 import omeWebhookRoutes from './routes/omeWebhook';
 import uploadRoutes from './routes/upload';
 import adminRoutes from './routes/admin'; // Import admin routes
+import batchRoutes from './routes/batch';
 import { logger } from './utils/logger';
 import { initializePassword } from './utils/initPassword';
 import mirotalkRoutes from './routes/mirotalk';
@@ -21,6 +24,26 @@ import OBSService from './services/obsService';
 import { initializeOIDC, initializeOIDCMiddleware } from './services/oidc-express';
 import { initializeTelegramService } from './services/telegram/initTelegram';
 import { initializeSocketIO, cleanupSocketIO } from './services/socket'; // Import Socket.IO functions (removed unused getIO)
+import { corsConfig } from './config/corsConfig';
+import { startBackupScheduler, stopBackupScheduler } from './services/backup';
+import { apiVersion } from './middleware/apiVersion';
+import { rejectWhenDbUnavailable } from './middleware/dbCircuitBreaker';
+import { requestTimeout } from './middleware/requestTimeout';
+import { assignRequestId } from './middleware/requestId';
+import { normalizeTrailingSlash } from './middleware/trailingSlash';
+import { prettyJson } from './middleware/prettyJson';
+import { forceHttps } from './middleware/forceHttps';
+import { readOnly } from './middleware/readOnly';
+import { accessLog } from './middleware/accessLog';
+import { reloadConfig } from './utils/configReload';
+import { discoverableOptions, unlessDiscovery } from './middleware/optionsAllow';
+import { trackInFlight, limitConcurrency, getInFlightCount, getInFlightRequests } from './middleware/inFlight';
+import { serverConfig } from './config/serverConfig';
+import { runSelfTest } from './utils/selfTest';
+import { logRoomNameTemplate } from './utils/roomNames';
+import { logStartupBanner } from './utils/startupBanner';
+import { isAllowedOrigin } from './utils/allowedOrigin';
+import { sessionService } from './services/sessions';
 
 dotenv.config();
 
@@ -41,12 +64,7 @@ app.set('trust proxy', 1);
 // CORS configuration
 const corsOptions = {
   origin: function (origin: string | undefined, callback: (err: Error | null, allow?: boolean) => void) {
-    const allowedOrigins = [
-      process.env.FRONTEND_URL || 'https://live.colourstream.colourbyrogers.co.uk', 
-      'http://localhost:8000',
-      'https://upload.colourstream.colourbyrogers.co.uk'
-    ];
-    if (!origin || allowedOrigins.indexOf(origin) !== -1) {
+    if (isAllowedOrigin(origin)) {
       callback(null, true);
     } else {
       console.log(`CORS blocked origin: ${origin}`);
@@ -55,23 +73,68 @@ const corsOptions = {
   },
   credentials: true,
   methods: ['GET', 'POST', 'PUT', 'DELETE', 'OPTIONS', 'PATCH', 'HEAD'],
-  allowedHeaders: ['Content-Type', 'Authorization', 'Tus-Resumable', 'Upload-Length', 'Upload-Metadata', 'Upload-Offset', 'X-Requested-With', 'X-HTTP-Method-Override'],
-  exposedHeaders: ['Location', 'Tus-Resumable', 'Upload-Offset', 'Upload-Length']
+  allowedHeaders: ['Content-Type', 'Authorization', 'Tus-Resumable', 'Upload-Length', 'Upload-Metadata', 'Upload-Offset', 'X-Requested-With', 'X-HTTP-Method-Override', 'If-Match'],
+  exposedHeaders: ['Location', 'Tus-Resumable', 'Upload-Offset', 'Upload-Length', 'X-RateLimit-Limit', 'X-RateLimit-Remaining', 'X-RateLimit-Reset', 'X-Total-Count', 'ETag'],
+  // Let browsers cache preflight results (only sent on OPTIONS responses); a getter so
+  // CORS_MAX_AGE follows config reloads
+  get maxAge() { return corsConfig.maxAge; }
 };
 
+// Browsers request a favicon from any origin they visit; don't let it count as a 404
+if (serverConfig.faviconNoContent) {
+  app.get('/favicon.ico', (_req, res) => {
+    res.status(204).end();
+  });
+}
+
+// Tag each request with an id for logs and error reports
+app.use(assignRequestId);
+
+// One log line per request, except probe paths in ACCESS_LOG_EXCLUDE
+app.use(accessLog);
+
+// Serve or redirect "/path/" as "/path" (TRAILING_SLASH)
+app.use(normalizeTrailingSlash);
+
+// Track in-flight requests so shutdown can report what is still draining
+app.use(trackInFlight);
+// Back-pressure: refuse work beyond MAX_CONCURRENT_REQUESTS instead of thrashing
+app.use(limitConcurrency);
+
+// Bound how long each route may take (REQUEST_TIMEOUT, ROUTE_TIMEOUTS)
+app.use(requestTimeout);
+
 // Security middleware
 app.use(ipBlocker);
 app.use(generalLimiter);
 
 // Standard middleware
-app.use(cors(corsOptions));
+// Plain OPTIONS requests skip cors (which answers every OPTIONS) so the routers can report Allow
+app.use(discoverableOptions);
+app.use(unlessDiscovery(cors(corsOptions)));
 app.use(express.json());
+// Negotiate the response envelope version (Accept: application/vnd.colourstream.v1+json)
+app.use(apiVersion);
+// Indent JSON for ?pretty=true or PRETTY_JSON=true
+app.use(prettyJson);
 
 // Get base path from environment variable
 const basePath = process.env.BASE_PATH || '/api';
 
 // Health check routes - no rate limiting or auth required
 app.use(`${basePath}/health`, healthRoutes);
+// Server clock for clock-skew checks - public and database-free
+app.use(`${basePath}/time`, timeRoutes);
+
+// Plain-HTTP requests past this point are redirected or rejected when FORCE_HTTPS is on.
+// Health checks stay reachable over HTTP for container probes that bypass the proxy.
+app.use(forceHttps);
+
+// With READ_ONLY=true, writes are refused from here on; sign-in and join flows stay open
+app.use(readOnly);
+
+// Everything below needs the database; refuse requests while its circuit breaker is open
+app.use(rejectWhenDbUnavailable);
 
 // Initialize OIDC middleware
 // Moving this line to after OIDC initialization in startServer function
@@ -80,6 +143,7 @@ app.use(`${basePath}/health`, healthRoutes);
 // Routes with base path
 app.use(`${basePath}/auth`, authRoutes);
 app.use(`${basePath}/rooms`, roomRoutes);
+app.use(`${basePath}/join`, joinRoutes);
 app.use(`${basePath}/obs`, obsRoutes);
 app.use(`${basePath}/omen`, omenRoutes);
 app.use(`${basePath}/security`, securityRoutes);
@@ -87,6 +151,7 @@ app.use('/api/mirotalk', mirotalkRoutes);
 app.use(`${basePath}/ome-webhook`, omeWebhookRoutes);
 app.use(`${basePath}/upload`, uploadRoutes);
 app.use(`${basePath}/admin`, adminRoutes); // Mount admin routes
+app.use(`${basePath}/batch`, batchRoutes);
 
 // Import routes from the main routes file which includes tusd hooks
 import routes from './routes';
@@ -99,6 +164,9 @@ const startServer = async () => {
   try {
     // Initialize the admin password hash
     await initializePassword();
+
+    // Keep sessions revoked before a restart rejected
+    await sessionService.loadRevoked();
     
     // Initialize OIDC
     const oidcInitialized = await initializeOIDC();
@@ -112,11 +180,28 @@ const startServer = async () => {
     
     // Initialize Telegram bot for upload monitoring
     initializeTelegramService();
+
+    // Report the default room name template and its placeholders
+    logRoomNameTemplate();
+
+    // Start scheduled database backups if configured
+    startBackupScheduler();
+
+    // One line summarizing the optional features this instance runs with
+    await logStartupBanner({ oidc: oidcInitialized });
     
-    const PORT = process.env.PORT || 5001;
+    const PORT = serverConfig.port;
     server.listen(PORT, () => {
       logger.info(`Server is running on port ${PORT}`);
       logger.info(`Health check available at ${basePath}/health`);
+
+      // Optionally verify the room handlers and database work end to end before serving traffic
+      if (serverConfig.selfTest) {
+        runSelfTest(`http://127.0.0.1:${PORT}${basePath}`, { readOnly: serverConfig.readOnly }).catch((error) => {
+          logger.error('Startup self-test failed:', error);
+          process.exit(1);
+        });
+      }
     });
   } catch (error) {
     logger.error('Failed to start server:', error);
@@ -127,26 +212,44 @@ const startServer = async () => {
 startServer();
 
 // Cleanup on server shutdown
-process.on('SIGTERM', () => {
-  logger.info('SIGTERM received. Cleaning up...');
-  wsService.cleanup();
-  obsService.cleanup();
-  cleanupSocketIO(); // Add Socket.IO cleanup
-  server.close(() => {
-    logger.info('Server closed');
-    process.exit(0);
-  });
-});
+const shutdown = (signal: string) => {
+  logger.info(`${signal} received. Cleaning up...`);
+
+  const inFlight = getInFlightCount();
+  if (inFlight > 0) {
+    logger.info(`Waiting for ${inFlight} in-flight request(s) to drain`);
+  }
+
+  // Give in-flight requests a bounded window to finish
+  const drainTimeout = setTimeout(() => {
+    const stuck = getInFlightRequests();
+    logger.warn(`Shutdown grace period of ${serverConfig.shutdownGracePeriodMs}ms exceeded with ${stuck.length} request(s) still in flight`, {
+      requests: stuck.map(request => `${request.method} ${request.path} (started ${request.startedAt.toISOString()})`)
+    });
+    process.exit(1);
+  }, serverConfig.shutdownGracePeriodMs);
 
-process.on('SIGINT', () => {
-  logger.info('SIGINT received. Cleaning up...');
   wsService.cleanup();
   obsService.cleanup();
   cleanupSocketIO(); // Add Socket.IO cleanup
+  stopBackupScheduler();
   server.close(() => {
+    clearTimeout(drainTimeout);
     logger.info('Server closed');
     process.exit(0);
   });
+};
+
+process.on('SIGTERM', () => shutdown('SIGTERM'));
+process.on('SIGINT', () => shutdown('SIGINT'));
+
+// Re-read .env and apply rate limits, log level, CORS and feature toggles without dropping streams
+process.on('SIGHUP', () => {
+  try {
+    reloadConfig();
+  } catch (error) {
+    logger.error('Configuration reload failed, keeping the current settings:', error);
+  }
 });
 
 export default server; 
\ No newline at end of file
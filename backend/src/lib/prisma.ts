@@ -1,16 +1,98 @@
 import { PrismaClient, Prisma } from '@prisma/client';
 import { logger } from '../utils/logger';
+import { dbCircuitBreaker, dbReadCircuitBreaker } from '../utils/circuitBreaker';
+import { dbConfig } from '../config/dbConfig';
+
+const LOG_LEVELS = [
+  { level: 'error', emit: 'stdout' },
+  { level: 'warn', emit: 'stdout' },
+  { level: 'info', emit: 'stdout' },
+  ...(process.env.NODE_ENV === 'development' || logger.level === 'debug' ? [{ level: 'query', emit: 'stdout' }] : []),
+] as Prisma.LogDefinition[];
 
 // Initialize Prisma Client with logging options
 const prisma = new PrismaClient({
-  log: [
-    { level: 'error', emit: 'stdout' },
-    { level: 'warn', emit: 'stdout' },
-    { level: 'info', emit: 'stdout' },
-    ...(process.env.NODE_ENV === 'development' ? [{ level: 'query', emit: 'stdout' }] : []),
-  ] as Prisma.LogDefinition[],
+  log: LOG_LEVELS,
 });
 
+const replica = dbConfig.readUrl
+  ? new PrismaClient({ log: LOG_LEVELS, datasourceUrl: dbConfig.readUrl })
+  : undefined;
+
+// Read-only handlers query the replica when DATABASE_READ_URL is set, otherwise the primary.
+// Replicas lag, so anything that must see a write it just made keeps using the primary client.
+// While the replica's breaker is open its queries go to the primary instead.
+export const prismaRead: PrismaClient = replica
+  ? new Proxy(replica, {
+    get: (_target, property) => {
+      const client = dbReadCircuitBreaker.allowRequest() ? replica : prisma;
+      const value = Reflect.get(client, property);
+      return typeof value === 'function' ? value.bind(client) : value;
+    },
+  })
+  : prisma;
+
+// Prisma error codes meaning the database itself is unreachable, timing out or overloaded
+const UNAVAILABLE_ERROR_CODES = new Set(['P1001', 'P1002', 'P1008', 'P1017', 'P2024']);
+
+const isUnavailableError = (error: unknown): boolean => {
+  if (error instanceof Prisma.PrismaClientKnownRequestError) {
+    return UNAVAILABLE_ERROR_CODES.has(error.code);
+  }
+  return error instanceof Prisma.PrismaClientInitializationError ||
+    error instanceof Prisma.PrismaClientRustPanicError;
+};
+
+// Feed query outcomes into a client's circuit breaker; constraint violations and the like
+// still mean the database answered, so only connection-level failures count against it
+const trackAvailability = (breaker: typeof dbCircuitBreaker): Prisma.Middleware => async (params, next) => {
+  try {
+    const result = await next(params);
+    breaker.recordSuccess();
+    return result;
+  } catch (error) {
+    if (isUnavailableError(error)) {
+      breaker.recordFailure();
+    } else {
+      breaker.recordSuccess();
+    }
+    throw error;
+  }
+};
+
+let slowQueryCount = 0;
+
+export const getSlowQueryStats = () => ({
+  thresholdMs: dbConfig.slowQueryThresholdMs,
+  count: slowQueryCount,
+});
+
+// Time every query and warn about those over DB_SLOW_QUERY_THRESHOLD, labelled Model.action
+// (e.g. Room.findMany, or raw.queryRaw for hand-written SQL). Failed queries are timed too.
+const logSlowQueries: Prisma.Middleware = async (params, next) => {
+  const startedAt = Date.now();
+  try {
+    return await next(params);
+  } finally {
+    const durationMs = Date.now() - startedAt;
+    if (dbConfig.slowQueryThresholdMs > 0 && durationMs >= dbConfig.slowQueryThresholdMs) {
+      slowQueryCount++;
+      logger.warn('Slow database query', {
+        query: `${params.model ?? 'raw'}.${params.action}`,
+        durationMs,
+        thresholdMs: dbConfig.slowQueryThresholdMs,
+      });
+    }
+  }
+};
+
+prisma.$use(trackAvailability(dbCircuitBreaker));
+prisma.$use(logSlowQueries);
+if (replica) {
+  replica.$use(trackAvailability(dbReadCircuitBreaker));
+  replica.$use(logSlowQueries);
+}
+
 // Add custom logging
 const originalConnect = prisma.$connect;
 prisma.$connect = async () => {
@@ -53,13 +135,13 @@ connectWithRetry()
 // Handle graceful shutdown
 process.on('SIGINT', async () => {
   logger.info('SIGINT received. Closing database connections...');
-  await prisma.$disconnect();
+  await Promise.all([prisma.$disconnect(), replica?.$disconnect()]);
   logger.info('Database connections closed');
 });
 
 process.on('SIGTERM', async () => {
   logger.info('SIGTERM received. Closing database connections...');
-  await prisma.$disconnect();
+  await Promise.all([prisma.$disconnect(), replica?.$disconnect()]);
   logger.info('Database connections closed');
 });
 
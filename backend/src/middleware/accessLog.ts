@@ -0,0 +1,46 @@
+import { Request, Response, NextFunction } from 'express';
+import { serverConfig } from '../config/serverConfig';
+import { logger } from '../utils/logger';
+
+interface AccessLogOptions {
+  enabled: boolean;
+  excludePaths: string[];
+}
+
+// "/api/health" also covers "/api/health/" and "/api/health/detailed"
+const isExcluded = (path: string, excludePaths: string[]): boolean =>
+  excludePaths.some(excluded => path === excluded || path.startsWith(`${excluded}/`));
+
+// Share-link tokens are credentials, so keep them out of the log: "/api/join/<token>" and "?token=<token>"
+const redactTokens = (url: string): string =>
+  url
+    .replace(/\/join\/[^/?#]+/, '/join/[redacted]')
+    .replace(/([?&]token=)[^&#]*/g, '$1[redacted]');
+
+// Log one line per finished request, skipping probe and metrics paths that would drown out real traffic
+export const createAccessLog = (options: AccessLogOptions) =>
+  (req: Request, res: Response, next: NextFunction) => {
+    if (!options.enabled || isExcluded(req.path, options.excludePaths)) {
+      return next();
+    }
+
+    const startedAt = Date.now();
+    res.on('finish', () => {
+      logger.info('HTTP request', {
+        method: req.method,
+        path: redactTokens(req.originalUrl),
+        status: res.statusCode,
+        durationMs: Date.now() - startedAt,
+        ip: req.ip,
+        requestId: req.id,
+      });
+    });
+
+    next();
+  };
+
+// Getters pick up ACCESS_LOG and ACCESS_LOG_EXCLUDE changes from a config reload
+export const accessLog = createAccessLog({
+  get enabled() { return serverConfig.accessLog; },
+  get excludePaths() { return serverConfig.accessLogExcludePaths; },
+});
@@ -0,0 +1,43 @@
+import { Request, Response, NextFunction } from 'express';
+
+// Response shape versions clients can ask for via Accept: application/vnd.colourstream.v<N>+json
+const SUPPORTED_VERSIONS = [1];
+const DEFAULT_VERSION = 1;
+
+const VENDOR_MEDIA_TYPE = /application\/vnd\.colourstream\.v(\d+)\+json/i;
+
+declare global {
+  namespace Express {
+    interface Request {
+      apiVersion?: number;
+      // The vendor media type the client asked for, unset when it sent none
+      apiMediaType?: string;
+    }
+  }
+}
+
+// Resolve the requested response version from the Accept header, defaulting to v1
+export const apiVersion = (req: Request, res: Response, next: NextFunction) => {
+  const match = VENDOR_MEDIA_TYPE.exec(req.headers.accept || '');
+
+  // Responses differ by Accept once a client opts in, so caches must key on it
+  res.vary('Accept');
+
+  if (!match) {
+    req.apiVersion = DEFAULT_VERSION;
+    return next();
+  }
+
+  const version = Number(match[1]);
+  if (!SUPPORTED_VERSIONS.includes(version)) {
+    return res.status(406).json({
+      status: 'error',
+      message: `Unsupported API version v${version}; supported versions: ${SUPPORTED_VERSIONS.map(v => `v${v}`).join(', ')}`,
+    });
+  }
+
+  req.apiVersion = version;
+  // Only versioned responses echo it back; errors and other routes keep their own content type
+  req.apiMediaType = `application/vnd.colourstream.v${version}+json`;
+  next();
+};
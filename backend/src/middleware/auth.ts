@@ -2,10 +2,18 @@ import { Request, Response, NextFunction } from 'express';
 import jwt from 'jsonwebtoken';
 import { logger } from '../utils/logger';
 import { AppError } from './errorHandler';
+import { isSessionRevoked } from '../services/sessionRevocations';
+import { secrets } from '../config/secrets';
 
 interface JwtPayload {
   userId: string;
   type: 'admin' | 'user';
+  // Passkey logins issue `role` instead of `type`
+  role?: string;
+  // Session id shared by every token issued from the same login
+  sid?: string;
+  iat?: number;
+  exp?: number;
 }
 
 declare global {
@@ -29,18 +37,23 @@ declare global {
 }
 
 export const verifyToken = async (token: string): Promise<JwtPayload> => {
+  let decoded: JwtPayload;
   try {
-    const decoded = jwt.verify(
+    decoded = jwt.verify(
       token,
-      process.env.ADMIN_AUTH_SECRET!
+      secrets.get('ADMIN_AUTH_SECRET')!
     ) as JwtPayload;
-    return decoded;
   } catch (error) {
     throw new AppError(401, 'Invalid or expired token');
   }
+
+  if (await isSessionRevoked(decoded.sid)) {
+    throw new AppError(401, 'Session has been revoked');
+  }
+  return decoded;
 };
 
-export function authenticateToken(req: Request, res: Response, next: NextFunction) {
+export async function authenticateToken(req: Request, res: Response, next: NextFunction) {
   const authHeader = req.headers['authorization'];
   const token = authHeader && authHeader.split(' ')[1];
 
@@ -49,12 +62,18 @@ export function authenticateToken(req: Request, res: Response, next: NextFunctio
     return res.status(401).json({ status: 'error', message: 'Authentication required' });
   }
 
+  let decoded: any;
   try {
-    const decoded = jwt.verify(token, process.env.ADMIN_AUTH_SECRET!) as any;
-    req.user = decoded;
-    next();
+    decoded = jwt.verify(token, secrets.get('ADMIN_AUTH_SECRET')!) as any;
   } catch (error) {
     logger.warn('Authentication failed: Invalid token', { error });
     return res.status(403).json({ status: 'error', message: 'Invalid or expired token' });
   }
+
+  if (await isSessionRevoked(decoded.sid)) {
+    logger.warn('Authentication failed: Session revoked', { sid: decoded.sid });
+    return res.status(401).json({ status: 'error', message: 'Session has been revoked' });
+  }
+  req.user = decoded;
+  next();
 }
@@ -0,0 +1,16 @@
+import { Request, Response, NextFunction } from 'express';
+import { dbCircuitBreaker } from '../utils/circuitBreaker';
+import { retryAfterSeconds } from '../utils/retryAfter';
+
+// Fail fast with 503 while the database breaker is open instead of queueing more queries
+export const rejectWhenDbUnavailable = (_req: Request, res: Response, next: NextFunction) => {
+  if (dbCircuitBreaker.allowRequest()) {
+    return next();
+  }
+
+  res.set('Retry-After', retryAfterSeconds(dbCircuitBreaker.retryAfterSeconds()));
+  return res.status(503).json({
+    status: 'error',
+    message: 'Database temporarily unavailable, please retry shortly',
+  });
+};
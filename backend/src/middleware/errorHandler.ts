@@ -5,6 +5,11 @@ interface ValidationError extends Error {
   errors?: Record<string, string>;
 }
 
+// body-parser tags its errors with a type, e.g. entity.parse.failed for malformed JSON
+interface BodyParserError extends Error {
+  type?: string;
+}
+
 export class AppError extends Error {
   constructor(
     public statusCode: number,
@@ -16,30 +21,81 @@ export class AppError extends Error {
   }
 }
 
+const escapeHtml = (value: string): string => value
+  .replace(/&/g, '&amp;')
+  .replace(/</g, '&lt;')
+  .replace(/>/g, '&gt;')
+  .replace(/"/g, '&quot;')
+  .replace(/'/g, '&#39;');
+
+const renderErrorPage = (statusCode: number, message: string): string => `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>${statusCode} ${escapeHtml(message)}</title>
+<style>
+  body { font-family: system-ui, sans-serif; background: #f5f5f7; color: #1d1d1f; display: flex; align-items: center; justify-content: center; min-height: 100vh; margin: 0; }
+  main { background: #fff; border-radius: 12px; padding: 2rem 2.5rem; box-shadow: 0 2px 12px rgba(0, 0, 0, 0.08); max-width: 32rem; }
+  h1 { font-size: 3rem; margin: 0 0 0.5rem; }
+  p { margin: 0; color: #515154; }
+</style>
+</head>
+<body>
+<main>
+  <h1>${statusCode}</h1>
+  <p>${escapeHtml(message)}</p>
+</main>
+</body>
+</html>
+`;
+
+// Browsers (Accept: text/html first) get a small HTML page; API clients keep the JSON envelope.
+// The status code is the same either way.
+const sendError = (req: Request, res: Response, statusCode: number, body: { message: string; errors?: unknown }): void => {
+  res.status(statusCode);
+  if (req.accepts(['json', 'html']) === 'html') {
+    res.type('html').send(renderErrorPage(statusCode, body.message));
+    return;
+  }
+  res.json({ status: 'error', ...body });
+};
+
 export const errorHandler = (
-  err: Error | AppError | ValidationError,
-  _req: Request,
+  err: Error | AppError | ValidationError | BodyParserError,
+  req: Request,
   res: Response,
-  _next: NextFunction
+  next: NextFunction
 ): void => {
   logger.error('Error:', {
     name: err.name,
     message: err.message,
     stack: err.stack,
+    requestId: req.id,
+    method: req.method,
+    path: req.originalUrl,
   });
 
+  // The response is already streaming; let Express close the connection
+  if (res.headersSent) {
+    next(err);
+    return;
+  }
+
   if (err instanceof AppError) {
-    res.status(err.statusCode).json({
-      status: 'error',
-      message: err.message,
-    });
+    sendError(req, res, err.statusCode, { message: err.message });
+    return;
+  }
+
+  // A body that isn't valid JSON is the client's mistake; an empty body never gets here,
+  // since express.json() hands handlers {} so optional-body endpoints see "no fields"
+  if ((err as BodyParserError).type === 'entity.parse.failed') {
+    sendError(req, res, 400, { message: 'Malformed JSON in request body' });
     return;
   }
 
   // Handle validation errors
   if (err.name === 'ValidationError') {
-    res.status(400).json({
-      status: 'error',
+    sendError(req, res, 400, {
       message: 'Validation Error',
       errors: (err as ValidationError).errors || err.message,
     });
@@ -48,16 +104,10 @@ export const errorHandler = (
 
   // Handle JWT errors
   if (err.name === 'JsonWebTokenError') {
-    res.status(401).json({
-      status: 'error',
-      message: 'Invalid token',
-    });
+    sendError(req, res, 401, { message: 'Invalid token' });
     return;
   }
 
   // Default error
-  res.status(500).json({
-    status: 'error',
-    message: 'Internal server error',
-  });
+  sendError(req, res, 500, { message: 'Internal server error' });
 }; 
\ No newline at end of file
@@ -0,0 +1,32 @@
+import { Request, Response, NextFunction } from 'express';
+import { serverConfig } from '../config/serverConfig';
+
+interface ForceHttpsOptions {
+  enabled: boolean;
+  mode: 'redirect' | 'reject';
+}
+
+// Refuse plain-HTTP requests that slipped past the TLS-terminating proxy. req.secure reads
+// X-Forwarded-Proto only from the trusted proxy ('trust proxy'), so clients can't spoof it.
+export const createForceHttps = (options: ForceHttpsOptions) =>
+  (req: Request, res: Response, next: NextFunction) => {
+    if (!options.enabled || req.secure) {
+      return next();
+    }
+
+    if (options.mode === 'reject') {
+      return res.status(400).json({
+        status: 'error',
+        message: 'HTTPS is required',
+      });
+    }
+
+    // 308 keeps the method and body, so a POST is retried as a POST over HTTPS
+    return res.redirect(308, `https://${req.get('host')}${req.originalUrl}`);
+  };
+
+// Both settings are hot-reloadable, so look them up on every request
+export const forceHttps = createForceHttps({
+  get enabled() { return serverConfig.forceHttps; },
+  get mode() { return serverConfig.forceHttpsMode; },
+});
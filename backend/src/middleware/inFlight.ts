@@ -0,0 +1,61 @@
+import { Request, Response, NextFunction } from 'express';
+import { serverConfig } from '../config/serverConfig';
+import { logger } from '../utils/logger';
+import { retryAfterSeconds } from '../utils/retryAfter';
+
+interface InFlightRequest {
+  method: string;
+  path: string;
+  startedAt: Date;
+}
+
+const inFlightRequests = new Map<number, InFlightRequest>();
+let nextRequestId = 0;
+let rejectedCount = 0;
+
+// Track every request until its response finishes or the connection closes
+export const trackInFlight = (req: Request, res: Response, next: NextFunction) => {
+  const id = nextRequestId++;
+  inFlightRequests.set(id, {
+    method: req.method,
+    path: req.originalUrl,
+    startedAt: new Date(),
+  });
+
+  const done = () => {
+    inFlightRequests.delete(id);
+  };
+  res.on('finish', done);
+  res.on('close', done);
+
+  next();
+};
+
+export const getInFlightCount = (): number => inFlightRequests.size;
+
+export const getInFlightRequests = (): InFlightRequest[] => Array.from(inFlightRequests.values());
+
+// Shed load with a 503 once more than MAX_CONCURRENT_REQUESTS are in flight (runs after trackInFlight)
+export const limitConcurrency = (req: Request, res: Response, next: NextFunction) => {
+  if (inFlightRequests.size <= serverConfig.maxConcurrentRequests) {
+    return next();
+  }
+
+  rejectedCount++;
+  logger.warn('Rejecting request: too many concurrent requests', {
+    method: req.method,
+    path: req.originalUrl,
+    inFlight: inFlightRequests.size,
+  });
+  res.set('Retry-After', retryAfterSeconds(1));
+  return res.status(503).json({
+    status: 'error',
+    message: 'Server is busy, please retry shortly',
+  });
+};
+
+export const getConcurrencyStats = () => ({
+  inFlight: inFlightRequests.size,
+  max: serverConfig.maxConcurrentRequests,
+  rejected: rejectedCount,
+});
@@ -0,0 +1,29 @@
+import { Request, Response, NextFunction, RequestHandler } from 'express';
+import { serverConfig } from '../config/serverConfig';
+
+// A bare OPTIONS request asks which methods a route supports;
+// a CORS preflight additionally carries Access-Control-Request-Method
+const isDiscoveryRequest = (req: Request): boolean =>
+  serverConfig.optionsAllow && req.method === 'OPTIONS' && !req.get('Access-Control-Request-Method');
+
+// Let discovery requests past middleware that would otherwise answer every OPTIONS itself (cors)
+export const unlessDiscovery = (middleware: RequestHandler): RequestHandler =>
+  (req, res, next) => (isDiscoveryRequest(req) ? next() : middleware(req, res, next));
+
+// Express's router answers an unhandled OPTIONS with an Allow header listing the route's
+// methods and the same list as a 200 body; send it as a bodyless 204 instead
+export const discoverableOptions = (req: Request, res: Response, next: NextFunction) => {
+  if (!isDiscoveryRequest(req)) {
+    return next();
+  }
+
+  const send = res.send.bind(res);
+  res.send = (body?: unknown) => {
+    if (res.statusCode === 200 && res.get('Allow')) {
+      res.status(204).end();
+      return res;
+    }
+    return send(body);
+  };
+  next();
+};
@@ -0,0 +1,21 @@
+import { Request, Response, NextFunction } from 'express';
+import { serverConfig } from '../config/serverConfig';
+
+const INDENT = 2;
+
+// Indent JSON responses for humans reading them with curl: every response when PRETTY_JSON=true,
+// or just this one with ?pretty=true. Compact JSON stays the default to keep payloads small.
+export const prettyJson = (req: Request, res: Response, next: NextFunction) => {
+  if (!serverConfig.prettyJson && req.query.pretty !== 'true') {
+    return next();
+  }
+
+  res.json = (body: unknown) => {
+    // Keep a media type set earlier (e.g. the negotiated vendor type)
+    if (!res.get('Content-Type')) {
+      res.type('json');
+    }
+    return res.send(JSON.stringify(body, null, INDENT));
+  };
+  next();
+};
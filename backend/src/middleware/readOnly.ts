@@ -0,0 +1,50 @@
+import { Request, Response, NextFunction } from 'express';
+import { serverConfig } from '../config/serverConfig';
+
+interface ReadOnlyOptions {
+  enabled: boolean;
+  basePath: string;
+}
+
+const SAFE_METHODS = ['GET', 'HEAD', 'OPTIONS'];
+
+// POST endpoints that sign in or issue join tokens. Some of these write, and are allowed to on
+// purpose: /auth/refresh rotates the refresh token, and refusing it would sign admins out of a
+// read-only instance. /batch is exempt because its sub-requests are replayed through this
+// middleware individually.
+const readOnlyExemptPaths = (basePath: string): string[] => [
+  `${basePath}/auth/webauthn/authenticate`,
+  `${basePath}/auth/oidc/token-exchange`,
+  `${basePath}/auth/refresh`,
+  `${basePath}/rooms/validate/`,
+  `${basePath}/ome-webhook/admission`,
+  `${basePath}/batch`,
+  '/api/mirotalk/join',
+  '/api/mirotalk/generate-token',
+];
+
+// Refuse every write with a 403 when the instance runs read-only (public demos). Unlike the
+// database circuit breaker this is a permanent stance, so there is no Retry-After.
+export const createReadOnly = (options: ReadOnlyOptions) => {
+  const exemptPaths = readOnlyExemptPaths(options.basePath);
+
+  return (req: Request, res: Response, next: NextFunction) => {
+    if (!options.enabled || SAFE_METHODS.includes(req.method)) {
+      return next();
+    }
+    if (exemptPaths.some(path => req.path === path || req.path.startsWith(path.endsWith('/') ? path : `${path}/`))) {
+      return next();
+    }
+
+    return res.status(403).json({
+      status: 'error',
+      message: 'Server is read-only',
+    });
+  };
+};
+
+// Read through a getter so READ_ONLY can be flipped by a config reload
+export const readOnly = createReadOnly({
+  get enabled() { return serverConfig.readOnly; },
+  basePath: process.env.BASE_PATH || '/api',
+});
@@ -0,0 +1,21 @@
+import { Request, Response, NextFunction } from 'express';
+import { randomUUID } from 'crypto';
+
+// Accept ids from trusted proxies only when they look like ids, not arbitrary log content
+const INCOMING_ID_PATTERN = /^[A-Za-z0-9._-]{1,128}$/;
+
+declare global {
+  namespace Express {
+    interface Request {
+      id?: string;
+    }
+  }
+}
+
+// Tag every request with an id (reusing X-Request-ID from the proxy when valid) and echo it back
+export const assignRequestId = (req: Request, res: Response, next: NextFunction) => {
+  const incoming = req.get('X-Request-ID');
+  req.id = incoming && INCOMING_ID_PATTERN.test(incoming) ? incoming : randomUUID();
+  res.set('X-Request-ID', req.id);
+  next();
+};
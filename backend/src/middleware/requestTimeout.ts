@@ -0,0 +1,50 @@
+import { Request, Response, NextFunction } from 'express';
+import { serverConfig } from '../config/serverConfig';
+import { logger } from '../utils/logger';
+
+// Once the 503 has gone out the handler is still running. Ignore whatever it eventually writes,
+// including streams piped into the response, instead of letting it throw "headers already sent".
+const ignoreLateResponse = (res: Response): void => {
+  const ignore = () => res;
+  res.json = ignore;
+  res.send = ignore;
+  res.redirect = ignore as Response['redirect'];
+  res.set = ignore as Response['set'];
+  res.header = ignore as Response['header'];
+  res.setHeader = ignore as Response['setHeader'];
+  res.writeHead = ignore as Response['writeHead'];
+  res.write = (() => true) as Response['write'];
+  res.end = ignore as Response['end'];
+};
+
+const timeoutFor = (path: string): number => {
+  const route = serverConfig.routeTimeouts.find(({ prefix }) => path.startsWith(prefix));
+  return route ? route.timeoutMs : serverConfig.requestTimeoutMs;
+};
+
+// Answer with a JSON 503 when a request outlives its route's timeout
+export const requestTimeout = (req: Request, res: Response, next: NextFunction) => {
+  const timeoutMs = timeoutFor(req.path);
+  if (timeoutMs === 0) {
+    return next();
+  }
+
+  const timer = setTimeout(() => {
+    if (res.headersSent) {
+      return;
+    }
+
+    logger.warn('Request timed out', { method: req.method, path: req.originalUrl, timeoutMs });
+    res.status(503).json({
+      status: 'error',
+      message: `Request timed out after ${timeoutMs}ms`,
+    });
+    ignoreLateResponse(res);
+  }, timeoutMs);
+
+  const clear = () => clearTimeout(timer);
+  res.on('finish', clear);
+  res.on('close', clear);
+
+  next();
+};
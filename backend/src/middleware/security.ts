@@ -1,38 +1,140 @@
 import { Request, Response, NextFunction } from 'express';
-import rateLimit from 'express-rate-limit';
+import rateLimit, { Options } from 'express-rate-limit';
+import jwt from 'jsonwebtoken';
+import net from 'net';
+import { authConfig } from '../config/authConfig';
+import { rateLimitConfig } from '../config/rateLimitConfig';
+import { secrets } from '../config/secrets';
 import { blockedIPService } from '../services/blockedIP';
 import { logger } from '../utils/logger';
+import { retryAfterSeconds } from '../utils/retryAfter';
 
-// Rate limiter for general requests - increased limits for production
-export const generalLimiter = rateLimit({
-    windowMs: 15 * 60 * 1000, // 15 minutes
-    max: 300, // Increased from 100 to 300 requests per windowMs
-    message: 'Too many requests from this IP, please try again later',
+// Header options shared by every limiter so clients can self-throttle before hitting a 429.
+// standardHeaders sends RateLimit-*, legacyHeaders sends X-RateLimit-Limit/Remaining/Reset,
+// both on every response from a limited route. The handler adds jitter to Retry-After on 429s.
+export const rateLimitHeaders = {
     standardHeaders: true,
-    legacyHeaders: false,
-    // Skip rate limiting for certain paths that need higher throughput
-    skip: (req) => {
-        // Skip rate limiting for static assets, websocket connections, and validation endpoints
-        return req.path.includes('/static') || 
-               req.path.includes('/assets') || 
-               req.path.includes('/ws') ||
-               req.path.startsWith('/api/rooms/validate') ||
-               req.path.startsWith('/api/obs/') ||
-               req.path.startsWith('/api/upload/') || // Skip all /api/upload/ routes
-               req.path.startsWith('/files') ||
-               req.path.startsWith('/upload');
+    legacyHeaders: true,
+    handler: async (req: Request, res: Response, _next: NextFunction, options: Options) => {
+        const resetTime = (req as Request & { rateLimit?: { resetTime?: Date } }).rateLimit?.resetTime;
+        const baseSeconds = resetTime ? Math.max(0, (resetTime.getTime() - Date.now()) / 1000) : options.windowMs / 1000;
+        res.set('Retry-After', retryAfterSeconds(baseSeconds));
 
+        const message = typeof options.message === 'function'
+            ? await options.message(req, res)
+            : options.message;
+        res.status(options.statusCode);
+        if (!res.writableEnded) {
+            res.send(message);
+        }
+    },
+};
+
+interface GeneralLimiterOptions {
+    keyStrategy: 'ip' | 'user';
+    ipMax: number;
+    userMax: number;
+}
 
+// The user a request is authenticated as, or undefined for anonymous/invalid tokens.
+// The signature is verified so a forged token cannot claim a fresh bucket.
+const authenticatedUser = (req: Request): string | undefined => {
+    const token = req.headers['authorization']?.split(' ')[1];
+    const secret = secrets.get('ADMIN_AUTH_SECRET');
+    if (!token || !secret) {
+        return undefined;
     }
-});
-// Stricter rate limiter for login attempts
-export const loginLimiter = rateLimit({
-    windowMs: 15 * 60 * 1000, // 15 minutes
-    max: 10, // Increased from 5 to 10 login attempts per windowMs
-    message: 'Too many login attempts from this IP, please try again later',
-    standardHeaders: true,
-    legacyHeaders: false,
-});
+    try {
+        const decoded = jwt.verify(token, secret) as { userId?: string };
+        return decoded.userId;
+    } catch (error) {
+        return undefined;
+    }
+};
+
+// Rate limiter for general requests. With the "user" strategy, authenticated requests are
+// bucketed per user so one user behind a shared NAT cannot exhaust everyone's limit.
+// Options are read per request, so a config reload can retune the limits of a running limiter.
+export const createGeneralLimiter = (options: GeneralLimiterOptions) => {
+    const userFor = (req: Request) => options.keyStrategy === 'user' ? authenticatedUser(req) : undefined;
+
+    return rateLimit({
+        windowMs: 15 * 60 * 1000, // 15 minutes
+        max: (req) => userFor(req) ? options.userMax : options.ipMax,
+        message: 'Too many requests from this IP, please try again later',
+        ...rateLimitHeaders,
+        keyGenerator: (req) => {
+            const user = userFor(req);
+            return user ? `user:${user}` : `ip:${req.ip}`;
+        },
+        // Skip rate limiting for certain paths that need higher throughput
+        skip: (req) => {
+            // Skip rate limiting for static assets, websocket connections, and validation endpoints
+            return req.path.includes('/static') || 
+                   req.path.includes('/assets') || 
+                   req.path.includes('/ws') ||
+                   req.path.startsWith('/api/rooms/validate') ||
+                   req.path.startsWith('/api/obs/') ||
+                   req.path.startsWith('/api/upload/') || // Skip all /api/upload/ routes
+                   req.path.startsWith('/files') ||
+                   req.path.startsWith('/upload');
+        }
+    });
+};
+
+export const generalLimiter = createGeneralLimiter(rateLimitConfig);
+
+interface LoginLimiterOptions {
+    loginBypassCidrs: string[];
+}
+
+// Build a matcher for the given CIDR ranges. IPv4-mapped IPv6 addresses (::ffff:10.0.0.1),
+// as reported for IPv4 clients on a dual-stack socket, are matched against the IPv4 ranges.
+const buildNetworkMatcher = (cidrs: string[]) => {
+    const blockList = new net.BlockList();
+    for (const cidr of cidrs) {
+        const [address, prefix] = cidr.split('/');
+        const family = net.isIP(address) === 6 ? 'ipv6' : 'ipv4';
+        blockList.addSubnet(address, Number(prefix), family);
+    }
+
+    return (ip: string | undefined): boolean => {
+        if (!ip || cidrs.length === 0) {
+            return false;
+        }
+        const mapped = ip.match(/^::ffff:(\d+\.\d+\.\d+\.\d+)$/i);
+        const address = mapped ? mapped[1] : ip;
+        const family = net.isIP(address);
+        if (family === 0) {
+            return false;
+        }
+        return blockList.check(address, family === 6 ? 'ipv6' : 'ipv4');
+    };
+};
+
+// Stricter rate limiter for login attempts. Clients in RATE_LIMIT_LOGIN_BYPASS_CIDRS
+// (matched on req.ip, which honours the trusted proxy) are not limited.
+// The matcher is rebuilt when a config reload swaps in a new range list.
+export const createLoginLimiter = (options: LoginLimiterOptions) => {
+    let cidrs = options.loginBypassCidrs;
+    let isTrusted = buildNetworkMatcher(cidrs);
+
+    return rateLimit({
+        windowMs: 15 * 60 * 1000, // 15 minutes
+        max: 10, // Increased from 5 to 10 login attempts per windowMs
+        message: 'Too many login attempts from this IP, please try again later',
+        skip: (req) => {
+            if (options.loginBypassCidrs !== cidrs) {
+                cidrs = options.loginBypassCidrs;
+                isTrusted = buildNetworkMatcher(cidrs);
+            }
+            return isTrusted(req.ip);
+        },
+        ...rateLimitHeaders,
+    });
+};
+
+export const loginLimiter = createLoginLimiter(rateLimitConfig);
 
 // Middleware to check if IP is blocked
 export const ipBlocker = async (req: Request, res: Response, next: NextFunction) => {
@@ -60,7 +162,37 @@ export const ipBlocker = async (req: Request, res: Response, next: NextFunction)
         next(error);
     }
 };
+
+// Failed-login delay for the nth failure from an IP: doubles from LOGIN_FAILURE_DELAY up to LOGIN_FAILURE_DELAY_MAX
+export const failedLoginDelayMs = (attempts: number, baseMs = authConfig.loginFailureDelayMs, maxMs = authConfig.loginFailureDelayMaxMs): number => {
+    if (baseMs <= 0 || attempts <= 0) {
+        return 0;
+    }
+    return Math.min(maxMs, baseMs * Math.pow(2, Math.min(attempts - 1, 30)));
+};
+
+// Wait out the failed-login delay, resolving early (with false) if the client disconnects
+const delayFailedLogin = (res: Response, attempts: number): Promise<boolean> => {
+    const delayMs = failedLoginDelayMs(attempts);
+    if (delayMs === 0) {
+        return Promise.resolve(true);
+    }
+
+    return new Promise(resolve => {
+        const onClose = () => {
+            clearTimeout(timer);
+            resolve(false);
+        };
+        const timer = setTimeout(() => {
+            res.off('close', onClose);
+            resolve(true);
+        }, delayMs);
+        res.once('close', onClose);
+    });
+};
+
 // Middleware to track failed login attempts and block IPs if necessary
+// Successful logins are answered immediately; only the failure paths call this
 export const trackLoginAttempts = async (req: Request, res: Response, next: NextFunction) => {
     try {
         const clientIP = req.ip || req.socket.remoteAddress || 'unknown';
@@ -81,6 +213,12 @@ export const trackLoginAttempts = async (req: Request, res: Response, next: Next
             return res.status(403).json({ error: 'IP has been blocked due to too many failed login attempts. Try again in 1 hour.' });
         }
 
+        // Slow down guessing; nobody is left to answer if the client gave up while waiting
+        const stillConnected = await delayFailedLogin(res, attempts);
+        if (!stillConnected) {
+            return;
+        }
+
         next();
     } catch (error) {
         logger.error('Error in login attempts tracking middleware:', error);
@@ -91,4 +229,4 @@ export const trackLoginAttempts = async (req: Request, res: Response, next: Next
 setInterval(() => {
     blockedIPService.cleanupOldRecords()
         .catch(error => logger.error('Error cleaning up old blocked IP records:', error));
-}, 24 * 60 * 60 * 1000); 
+}, 24 * 60 * 60 * 1000).unref(); // Don't keep the process alive just for cleanup 
@@ -0,0 +1,27 @@
+import { Request, Response, NextFunction } from 'express';
+import { serverConfig } from '../config/serverConfig';
+
+// Normalize "/path/" to "/path" according to TRAILING_SLASH, so every route answers the same
+// whichever form a client's HTTP library sends. The root path "/" is left alone.
+export const normalizeTrailingSlash = (req: Request, res: Response, next: NextFunction) => {
+  const mode = serverConfig.trailingSlash;
+  const queryStart = req.url.indexOf('?');
+  const pathname = queryStart === -1 ? req.url : req.url.slice(0, queryStart);
+
+  if (mode === 'off' || pathname.length <= 1 || !pathname.endsWith('/')) {
+    return next();
+  }
+
+  // Collapse leading slashes too, so "//evil.example/" can't become a protocol-relative redirect
+  const normalized = pathname.replace(/\/+$/, '').replace(/^\/+/, '/') || '/';
+  const query = queryStart === -1 ? '' : req.url.slice(queryStart);
+
+  if (mode === 'redirect') {
+    // 308 keeps the method and body for non-GET requests, which a 301 would not guarantee
+    const status = req.method === 'GET' || req.method === 'HEAD' ? 301 : 308;
+    return res.redirect(status, `${normalized}${query}`);
+  }
+
+  req.url = `${normalized}${query}`;
+  next();
+};
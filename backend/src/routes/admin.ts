@@ -1,9 +1,47 @@
-import express, { Request, Response } from 'express';
-import { authenticateToken } from '../middleware/auth'; // Assuming admin routes need authentication
+import { Request, Response, NextFunction } from 'express';
+import fs from 'fs';
+import os from 'os';
+import path from 'path';
+import { authenticateToken, verifyToken } from '../middleware/auth'; // Assuming admin routes need authentication
+import { AppError } from '../middleware/errorHandler';
 import { uploadTracker } from '../services/uploads/uploadTracker';
 import { logger } from '../utils/logger';
+import { createBackup, dumpDatabase } from '../services/backup';
+import { sessionService } from '../services/sessions';
+import { getEffectiveConfig } from '../utils/effectiveConfig';
+import { reloadConfig } from '../utils/configReload';
+import { asyncRouter } from '../utils/asyncRouter';
 
-const router = express.Router();
+const router = asyncRouter();
+
+// Only one snapshot download may run at a time
+let snapshotDownloadInProgress = false;
+
+// GET endpoint describing the identity behind the current token
+router.get('/whoami', async (req: Request, res: Response, next: NextFunction) => {
+  try {
+    const authHeader = req.headers['authorization'];
+    const token = authHeader && authHeader.split(' ')[1];
+
+    if (!token) {
+      throw new AppError(401, 'Authentication required');
+    }
+
+    // verifyToken rejects invalid or expired tokens with a 401
+    const decoded = await verifyToken(token);
+
+    res.json({
+      status: 'success',
+      data: {
+        username: decoded.userId,
+        role: decoded.role || decoded.type,
+        expiresAt: decoded.exp ? new Date(decoded.exp * 1000).toISOString() : null,
+      },
+    });
+  } catch (error) {
+    next(error);
+  }
+});
 
 // GET endpoint to retrieve currently active uploads
 router.get('/active-uploads', authenticateToken, (_req: Request, res: Response) => { // Prefix req with _
@@ -39,4 +77,115 @@ router.get('/active-uploads', authenticateToken, (_req: Request, res: Response)
   }
 });
 
+// GET endpoint describing the effective configuration, with secrets redacted
+router.get('/config', authenticateToken, (_req: Request, res: Response) => {
+  res.json({
+    status: 'success',
+    data: getEffectiveConfig(),
+  });
+});
+
+// GET endpoint listing active (non-revoked, unexpired) admin sessions
+router.get('/sessions', authenticateToken, async (_req: Request, res: Response, next: NextFunction) => {
+  try {
+    const sessions = await sessionService.list();
+    res.json({
+      status: 'success',
+      data: { sessions },
+    });
+  } catch (error) {
+    next(error);
+  }
+});
+
+// DELETE endpoint to revoke a session by its id
+router.delete('/sessions/:jti', authenticateToken, async (req: Request, res: Response, next: NextFunction) => {
+  try {
+    // Responds 404 via AppError when the session is unknown or already revoked
+    await sessionService.revoke(req.params.jti);
+    res.status(204).send();
+  } catch (error) {
+    next(error);
+  }
+});
+
+// POST endpoint to re-read .env and apply the hot-reloadable settings (same as SIGHUP)
+router.post('/reload', authenticateToken, (_req: Request, res: Response) => {
+  try {
+    res.json({
+      status: 'success',
+      data: reloadConfig(),
+    });
+  } catch (error) {
+    logger.error('Configuration reload failed, keeping the current settings:', error);
+    throw new AppError(500, `Configuration reload failed: ${error instanceof Error ? error.message : String(error)}`);
+  }
+});
+
+// POST endpoint to create an on-demand database backup
+router.post('/backup', authenticateToken, async (_req: Request, res: Response) => {
+  try {
+    const backup = await createBackup();
+
+    res.status(201).json({
+      status: 'success',
+      data: backup,
+    });
+  } catch (error) {
+    logger.error('Failed to create database backup:', error);
+    res.status(500).json({
+      status: 'error',
+      message: 'Failed to create database backup',
+    });
+  }
+});
+
+// GET endpoint to download a fresh database snapshot
+router.get('/backup/download', authenticateToken, async (_req: Request, res: Response) => {
+  if (snapshotDownloadInProgress) {
+    return res.status(409).json({
+      status: 'error',
+      message: 'A snapshot download is already in progress',
+    });
+  }
+
+  snapshotDownloadInProgress = true;
+  const fileName = `colourstream-${new Date().toISOString().replace(/[:.]/g, '-')}.dump`;
+  const snapshotPath = path.join(os.tmpdir(), fileName);
+
+  const cleanup = () => {
+    snapshotDownloadInProgress = false;
+    fs.promises.unlink(snapshotPath).catch(error => {
+      if (error.code !== 'ENOENT') {
+        logger.error('Failed to remove temporary database snapshot:', error);
+      }
+    });
+  };
+
+  try {
+    const snapshot = await dumpDatabase(snapshotPath);
+    logger.info(`Admin request for database snapshot download. Size: ${snapshot.size} bytes`);
+
+    res.setHeader('Content-Type', 'application/octet-stream');
+    res.setHeader('Content-Length', snapshot.size.toString());
+    res.setHeader('Content-Disposition', `attachment; filename="${fileName}"`);
+
+    const stream = fs.createReadStream(snapshotPath);
+    stream.on('error', error => {
+      logger.error('Failed to stream database snapshot:', error);
+      res.destroy(error);
+    });
+    // 'close' fires whether the download finished or the client went away
+    res.on('close', cleanup);
+    stream.pipe(res);
+  } catch (error) {
+    cleanup();
+    logger.error('Failed to create database snapshot for download:', error);
+    res.status(500).json({
+      status: 'error',
+      message: 'Failed to create database snapshot',
+    });
+  }
+});
+
 export default router;
\ No newline at end of file
@@ -1,4 +1,4 @@
-import express, { Request, Response, NextFunction } from 'express';
+import { Request, Response, NextFunction } from 'express';
 import jwt from 'jsonwebtoken';
 import { AppError } from '../middleware/errorHandler';
 import { loginLimiter, trackLoginAttempts } from '../middleware/security';
@@ -17,8 +17,12 @@ import {
   updateOIDCConfigInDB
 } from '../services/oidc-express';
 import { requiresAuth } from 'express-openid-connect';
+import { authConfig } from '../config/authConfig';
+import { refreshTokenService } from '../services/refreshTokens';
+import { secrets } from '../config/secrets';
+import { asyncRouter } from '../utils/asyncRouter';
 
-const router = express.Router();
+const router = asyncRouter();
 
 // WebAuthn configuration
 const rpName = 'ColourStream Admin';
@@ -42,6 +46,18 @@ function bigIntToNumber(value: bigint): number {
   return number;
 }
 
+// Issue an access token plus a refresh token for a new admin session.
+// The access token carries the session id (sid) so the session can be revoked.
+const issueAdminTokens = async (claims: { userId: string; [claim: string]: unknown }) => {
+  const { refreshToken, familyId } = await refreshTokenService.issue(claims.userId);
+  const token = jwt.sign(
+    { ...claims, sid: familyId },
+    secrets.get('ADMIN_AUTH_SECRET')!,
+    { expiresIn: authConfig.tokenTtlSeconds }
+  );
+  return { token, refreshToken };
+};
+
 // OIDC configuration endpoint
 router.get('/oidc/config', async (_req: Request, res: Response, next: NextFunction) => {
   try {
@@ -98,7 +114,7 @@ router.get('/oidc/profile', requiresAuth(), (req: Request, res: Response) => {
 });
 
 // OIDC token endpoint - generates a JWT token for the authenticated user
-router.get('/oidc/token', requiresAuth(), (req: Request, res: Response) => {
+router.get('/oidc/token', requiresAuth(), async (req: Request, res: Response) => {
   try {
     // Check if user info contains required fields
     if (!req.oidc.user || !req.oidc.user.sub) {
@@ -107,7 +123,7 @@ router.get('/oidc/token', requiresAuth(), (req: Request, res: Response) => {
     }
     
     // Generate JWT token
-    const token = jwt.sign(
+    const { token, refreshToken } = await issueAdminTokens(
       { 
         userId: 'admin', 
         type: 'admin',
@@ -115,9 +131,7 @@ router.get('/oidc/token', requiresAuth(), (req: Request, res: Response) => {
           sub: req.oidc.user.sub,
           provider: req.oidc.user.iss
         }
-      },
-      process.env.ADMIN_AUTH_SECRET!,
-      { expiresIn: '7d' }
+      }
     );
     
     logger.info('Generated JWT token for OIDC user', {
@@ -128,6 +142,7 @@ router.get('/oidc/token', requiresAuth(), (req: Request, res: Response) => {
       status: 'success',
       data: {
         token,
+        refreshToken,
         user: req.oidc.user
       }
     });
@@ -310,7 +325,7 @@ router.post('/oidc/token-exchange', loginLimiter, async (req: Request, res: Resp
       const sub = tokenData.sub || 'unknown';
       
       // Generate JWT token for the user
-      const token = jwt.sign(
+      const { token, refreshToken } = await issueAdminTokens(
         { 
           userId: 'admin', 
           type: 'admin',
@@ -318,9 +333,7 @@ router.post('/oidc/token-exchange', loginLimiter, async (req: Request, res: Resp
             sub,
             provider: config.providerName
           }
-        },
-        process.env.ADMIN_AUTH_SECRET!,
-        { expiresIn: '7d' }
+        }
       );
       
       logger.info('Generated JWT token for OIDC user from token data');
@@ -329,6 +342,7 @@ router.post('/oidc/token-exchange', loginLimiter, async (req: Request, res: Resp
         status: 'success',
         data: {
           token,
+          refreshToken,
           user: {
             sub,
             ...tokenData
@@ -362,7 +376,7 @@ router.post('/oidc/token-exchange', loginLimiter, async (req: Request, res: Resp
     }
     
     // Generate JWT token for the user
-    const token = jwt.sign(
+    const { token, refreshToken } = await issueAdminTokens(
       { 
         userId: 'admin', 
         type: 'admin',
@@ -370,9 +384,7 @@ router.post('/oidc/token-exchange', loginLimiter, async (req: Request, res: Resp
           sub: userInfo.sub,
           provider: config.providerName
         }
-      },
-      process.env.ADMIN_AUTH_SECRET!,
-      { expiresIn: '7d' }
+      }
     );
     
     logger.info('Generated JWT token for OIDC user', {
@@ -383,6 +395,7 @@ router.post('/oidc/token-exchange', loginLimiter, async (req: Request, res: Resp
       status: 'success',
       data: {
         token,
+        refreshToken,
         user: userInfo
       }
     });
@@ -417,7 +430,7 @@ router.post('/webauthn/register', async (req: Request, res: Response, next: Next
             }
             const token = authHeader.split(' ')[1];
             try {
-                jwt.verify(token, process.env.ADMIN_AUTH_SECRET!);
+                jwt.verify(token, secrets.get('ADMIN_AUTH_SECRET')!);
                 // Token is valid, proceed.
             } catch (error) {
                 // Invalid token.
@@ -556,17 +569,15 @@ router.post('/webauthn/register/verify', async (req: Request, res: Response, nex
       // Generate a token upon successful registration verification if needed
       // This depends on whether the user should be logged in immediately after registration
       // For the /setup-passkey flow, generating a token might be desired.
-      const token = jwt.sign(
-        { userId: userId, type: 'admin' }, // Use the determined userId
-        process.env.ADMIN_AUTH_SECRET!,
-        { expiresIn: '7d' }
+      const { token, refreshToken } = await issueAdminTokens(
+        { userId: userId, type: 'admin' } // Use the determined userId
       );
 
       res.json({ 
         status: 'success',
         message: 'Passkey registered successfully',
         verified: true,
-        data: { token } // Optionally return the token
+        data: { token, refreshToken } // Optionally return the token
       });
     } else {
       logger.error('Registration verification failed');
@@ -746,10 +757,8 @@ router.post('/webauthn/authenticate/verify', loginLimiter, async (req: Request,
                 currentChallenge = undefined;
 
                 // Generate a JWT token
-                const token = jwt.sign(
-                    { userId: 'admin', role: 'admin' },
-                    process.env.ADMIN_AUTH_SECRET!,
-                    { expiresIn: '7d' }
+                const { token, refreshToken } = await issueAdminTokens(
+                    { userId: 'admin', role: 'admin' }
                 );
 
                 logger.info('Authentication successful, token generated');
@@ -758,7 +767,8 @@ router.post('/webauthn/authenticate/verify', loginLimiter, async (req: Request,
                 return res.json({ 
                     status: 'success',
                     data: {
-                        token
+                        token,
+                        refreshToken
                     }
                 });
             } else {
@@ -896,10 +906,8 @@ router.post('/webauthn/first-time-setup/verify', async (req: Request, res: Respo
     currentChallenge = undefined;
 
     // Generate a token for the admin user
-    const token = jwt.sign(
-      { userId: 'admin', type: 'admin' },
-      process.env.ADMIN_AUTH_SECRET!,
-      { expiresIn: '7d' }
+    const { token, refreshToken } = await issueAdminTokens(
+      { userId: 'admin', type: 'admin' }
     );
 
     logger.info('First-time setup passkey registered successfully');
@@ -914,6 +922,7 @@ router.post('/webauthn/first-time-setup/verify', async (req: Request, res: Respo
           createdAt: credential.createdAt,
         },
         token,
+        refreshToken,
       },
     });
   } catch (error) {
@@ -921,6 +930,38 @@ router.post('/webauthn/first-time-setup/verify', async (req: Request, res: Respo
   }
 });
 
+// Exchange a refresh token for a new access token, rotating the refresh token
+router.post('/refresh', loginLimiter, async (req: Request, res: Response, next: NextFunction) => {
+  try {
+    const { refreshToken } = req.body;
+
+    if (!refreshToken || typeof refreshToken !== 'string') {
+      throw new AppError(400, 'Missing refresh token');
+    }
+
+    const rotated = await refreshTokenService.rotate(refreshToken);
+
+    const token = jwt.sign(
+      { userId: rotated.userId, type: 'admin', sid: rotated.familyId },
+      secrets.get('ADMIN_AUTH_SECRET')!,
+      { expiresIn: authConfig.tokenTtlSeconds }
+    );
+
+    logger.info('Access token refreshed', { userId: rotated.userId });
+
+    res.json({
+      status: 'success',
+      data: {
+        token,
+        refreshToken: rotated.refreshToken,
+        refreshTokenExpiresAt: rotated.expiresAt.toISOString()
+      }
+    });
+  } catch (error) {
+    next(error);
+  }
+});
+
 // Check if system needs first-time setup
 router.get('/setup-required', async (_req: Request, res: Response, next: NextFunction) => {
   try {
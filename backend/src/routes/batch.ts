@@ -0,0 +1,142 @@
+import { Request, Response, NextFunction } from 'express';
+import { authenticateToken } from '../middleware/auth';
+import { AppError } from '../middleware/errorHandler';
+import { serverConfig } from '../config/serverConfig';
+import { logger } from '../utils/logger';
+import { asyncRouter } from '../utils/asyncRouter';
+
+const router = asyncRouter();
+
+const MAX_SUB_REQUESTS = 20;
+const ALLOWED_METHODS = ['GET', 'POST', 'PUT', 'PATCH', 'DELETE'];
+// Headers a sub-request may set itself, e.g. If-Match for conditional updates or the merge-patch content type
+const ALLOWED_HEADERS = ['If-Match', 'Content-Type'];
+
+interface SubRequest {
+  method: string;
+  path: string;
+  body?: unknown;
+  headers: Record<string, string>;
+}
+
+interface SubResponse {
+  status: number;
+  body: unknown;
+}
+
+const basePath = process.env.BASE_PATH || '/api';
+
+// Sub-request paths are relative to the API base path and may not escape it or recurse into /batch
+const validateSubRequest = (entry: unknown, index: number): SubRequest => {
+  const { method, path, body, headers = {} } = (entry || {}) as Partial<SubRequest>;
+
+  if (typeof method !== 'string' || !ALLOWED_METHODS.includes(method.toUpperCase())) {
+    throw new AppError(400, `Request ${index}: method must be one of ${ALLOWED_METHODS.join(', ')}`);
+  }
+  if (typeof path !== 'string' || !path.startsWith('/') || path.startsWith('//') || path.includes('..')) {
+    throw new AppError(400, `Request ${index}: path must be an absolute API path such as /rooms`);
+  }
+  if (path === '/batch' || path.startsWith('/batch/') || path.startsWith('/batch?')) {
+    throw new AppError(400, `Request ${index}: batches cannot be nested`);
+  }
+  if (typeof headers !== 'object' || headers === null || Array.isArray(headers)) {
+    throw new AppError(400, `Request ${index}: headers must be an object`);
+  }
+
+  // Header names are case-insensitive; pass them on under their usual spelling
+  const allowedHeaders: Record<string, string> = {};
+  for (const [name, value] of Object.entries(headers)) {
+    const allowed = ALLOWED_HEADERS.find(header => header.toLowerCase() === name.toLowerCase());
+    if (!allowed || typeof value !== 'string') {
+      throw new AppError(400, `Request ${index}: headers may only set ${ALLOWED_HEADERS.join(' and ')} to strings, got ${name}`);
+    }
+    allowedHeaders[allowed] = value;
+  }
+
+  return { method: method.toUpperCase(), path, body, headers: allowedHeaders };
+};
+
+// Replay a sub-request against this server so it runs through the same middleware and handlers.
+// Each one is a request of its own: it takes its own MAX_CONCURRENT_REQUESTS slot and REQUEST_TIMEOUT.
+const dispatch = async (req: Request, subRequest: SubRequest): Promise<SubResponse> => {
+  const headers: Record<string, string> = {
+    'X-Forwarded-For': req.ip || '',
+    // The loopback hop is plain http; carry the caller's scheme, as the proxy would, so FORCE_HTTPS lets it through
+    'X-Forwarded-Proto': req.protocol,
+  };
+  if (req.headers.authorization) {
+    headers['Authorization'] = req.headers.authorization;
+  }
+  if (req.id) {
+    headers['X-Request-ID'] = req.id;
+  }
+  if (subRequest.body !== undefined) {
+    headers['Content-Type'] = 'application/json';
+  }
+  Object.assign(headers, subRequest.headers);
+
+  const response = await fetch(`http://127.0.0.1:${serverConfig.port}${basePath}${subRequest.path}`, {
+    method: subRequest.method,
+    headers,
+    body: subRequest.body !== undefined ? JSON.stringify(subRequest.body) : undefined,
+  });
+
+  const text = await response.text();
+  let body: unknown = text || null;
+  try {
+    body = text ? JSON.parse(text) : null;
+  } catch (error) {
+    // Non-JSON responses are returned as text
+  }
+
+  return { status: response.status, body };
+};
+
+// Execute several API requests in order in one round trip
+router.post('/', authenticateToken, async (req: Request, res: Response, next: NextFunction) => {
+  try {
+    const { requests, stopOnError = false } = Array.isArray(req.body) ? { requests: req.body } : (req.body || {});
+
+    if (!Array.isArray(requests) || requests.length === 0) {
+      throw new AppError(400, 'requests must be a non-empty array');
+    }
+    if (requests.length > MAX_SUB_REQUESTS) {
+      throw new AppError(400, `A batch may contain at most ${MAX_SUB_REQUESTS} requests`);
+    }
+
+    const subRequests = requests.map(validateSubRequest);
+    const responses: SubResponse[] = [];
+
+    for (const subRequest of subRequests) {
+      // REQUEST_TIMEOUT has already answered the batch with a 503; don't start more work nobody will see
+      if (res.headersSent) {
+        logger.warn('Batch abandoned after the request timed out', { completed: responses.length, total: subRequests.length });
+        return;
+      }
+      const response = await dispatch(req, subRequest);
+      responses.push(response);
+
+      if (stopOnError === true && response.status >= 400) {
+        logger.info('Batch stopped on failed sub-request', {
+          method: subRequest.method,
+          path: subRequest.path,
+          status: response.status,
+        });
+        break;
+      }
+    }
+
+    res.json({
+      status: 'success',
+      data: {
+        responses,
+        completed: responses.length,
+        total: subRequests.length,
+      },
+    });
+  } catch (error) {
+    next(error);
+  }
+});
+
+export default router;
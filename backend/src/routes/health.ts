@@ -1,8 +1,12 @@
-import express from 'express';
-import prisma from '../lib/prisma';
+import prisma, { getSlowQueryStats } from '../lib/prisma';
 import { logger } from '../utils/logger';
+import { getInFlightCount, getConcurrencyStats } from '../middleware/inFlight';
+import { dbCircuitBreaker, dbReadCircuitBreaker } from '../utils/circuitBreaker';
+import { getWebSocketStats } from '../services/websocket';
+import { dbConfig } from '../config/dbConfig';
+import { asyncRouter } from '../utils/asyncRouter';
 
-const router = express.Router();
+const router = asyncRouter();
 
 /**
  * @route GET /api/health
@@ -62,7 +66,10 @@ router.get('/detailed', async (_req, res) => {
       nodeVersion: process.version,
       platform: process.platform,
       memoryUsage: process.memoryUsage(),
-      uptime: process.uptime()
+      uptime: process.uptime(),
+      inFlightRequests: getInFlightCount(),
+      concurrency: getConcurrencyStats(),
+      webSocketClients: getWebSocketStats()
     };
     
     // Get database info if connected
@@ -92,7 +99,10 @@ router.get('/detailed', async (_req, res) => {
       timestamp: new Date().toISOString(),
       database: {
         connected: dbConnected,
-        info: dbInfo
+        info: dbInfo,
+        circuitBreaker: dbCircuitBreaker.getStatus(),
+        readReplicaCircuitBreaker: dbConfig.readUrl ? dbReadCircuitBreaker.getStatus() : null,
+        slowQueries: getSlowQueryStats()
       },
       system: systemInfo
     });
@@ -1,4 +1,3 @@
-import express from 'express';
 import authRoutes from './auth';
 import roomsRoutes from './rooms';
 import securityRoutes from './security';
@@ -7,11 +6,12 @@ import omenRoutes from './omen';
 import mirotalkRoutes from './mirotalk';
 import omeWebhookRoutes from './omeWebhook';
 import uploadRoutes from './upload';
+import { asyncRouter } from '../utils/asyncRouter';
 // Removed import for tusHookRoutes as it's handled by script hooks now
 // import tusHookRoutes from './tusHookRoutes';
 
 
-const router = express.Router();
+const router = asyncRouter();
 
 router.use('/auth', authRoutes);
 router.use('/rooms', roomsRoutes);
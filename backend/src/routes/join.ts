@@ -0,0 +1,54 @@
+import { Request, Response } from 'express';
+import { AppError } from '../middleware/errorHandler';
+import { shareLinkService } from '../services/shareLinks';
+import { unavailableReason } from '../utils/roomSchedule';
+import { asyncRouter } from '../utils/asyncRouter';
+
+const router = asyncRouter();
+
+/**
+ * @route GET /api/join/:token
+ * @desc Redeem a room share link for the same join data POST /rooms/validate/:id returns to guests
+ * @access Public (the signed token is the credential)
+ */
+router.get('/:token', async (req: Request, res: Response) => {
+  res.set('Cache-Control', 'no-store');
+  try {
+    const link = await shareLinkService.resolve(req.params.token);
+    const { room } = link;
+
+    if (new Date() > room.expiryDate) {
+      throw new AppError(403, 'Room has expired');
+    }
+    const unavailable = unavailableReason(room);
+    if (unavailable) {
+      throw new AppError(423, unavailable);
+    }
+
+    // Burn one-time links only once the room is known to be joinable, so an early click doesn't waste them
+    if (link.oneTime) {
+      await shareLinkService.consume(link.id);
+    }
+
+    return res.status(200).json({
+      status: 'success',
+      data: {
+        roomId: room.id,
+        name: room.name,
+        mirotalkRoomId: room.mirotalkRoomId,
+        streamKey: room.streamKey,
+        mirotalkToken: room.mirotalkToken,
+        isPresenter: false,
+        expiresAt: link.expiresAt,
+      }
+    });
+  } catch (error) {
+    if (error instanceof AppError) {
+      return res.status(error.statusCode).json({ status: 'error', message: error.message });
+    }
+    console.error("Error redeeming share link:", error);
+    return res.status(500).json({ status: 'error', message: 'Failed to redeem share link' });
+  }
+});
+
+export default router;
@@ -1,12 +1,14 @@
-import express, { Request, Response, NextFunction } from 'express';
+import { Request, Response, NextFunction } from 'express';
 import { authenticateToken } from '../middleware/auth';
 import { AppError } from '../middleware/errorHandler';
 import { logger } from '../utils/logger';
 import jwt from 'jsonwebtoken';
 import CryptoJS from 'crypto-js';
 import prisma from '../lib/prisma';
+import { secrets } from '../config/secrets';
+import { asyncRouter } from '../utils/asyncRouter';
 
-const router = express.Router();
+const router = asyncRouter();
 
 // Unused type - keeping for documentation
 // type StringValue = string & { __brand: '_StringValue' };
@@ -72,7 +74,8 @@ router.post('/join', authenticateToken, async (req: Request<{}, {}, MiroTalkJoin
       customPasswordLength: customPassword?.length
     });
 
-    if (!process.env.JWT_KEY) {
+    const jwtKey = secrets.get('JWT_KEY');
+    if (!jwtKey) {
       throw new AppError(500, 'JWT_KEY environment variable is not set');
     }
 
@@ -102,9 +105,10 @@ router.post('/join', authenticateToken, async (req: Request<{}, {}, MiroTalkJoin
       // If no custom credentials provided, try to get from environment
       if (!username || !password) {
         try {
-          if (process.env.HOST_USERS) {
+          const hostUsers = secrets.get('HOST_USERS');
+          if (hostUsers) {
             // Remove any surrounding quotes that might be included in the env var
-            const cleanedHostUsers = process.env.HOST_USERS.replace(/^['"]|['"]$/g, '');
+            const cleanedHostUsers = hostUsers.replace(/^['"]|['"]$/g, '');
             logger.debug('Parsing HOST_USERS:', { cleanedHostUsers });
             
             const parsedUsers = JSON.parse(cleanedHostUsers);
@@ -151,7 +155,7 @@ router.post('/join', authenticateToken, async (req: Request<{}, {}, MiroTalkJoin
 
     // Encrypt payload using AES
     const payloadString = JSON.stringify(tokenPayload);
-    const encryptedPayload = CryptoJS.AES.encrypt(payloadString, process.env.JWT_KEY).toString();
+    const encryptedPayload = CryptoJS.AES.encrypt(payloadString, jwtKey).toString();
 
     // Create JWT token with string expiration format as expected by MiroTalk
     // Convert the string expire value to a number of seconds for jwt.sign
@@ -159,7 +163,7 @@ router.post('/join', authenticateToken, async (req: Request<{}, {}, MiroTalkJoin
     
     const jwtToken = jwt.sign(
       { data: encryptedPayload },
-      process.env.JWT_KEY || '',
+      jwtKey,
       { expiresIn: expireInSeconds }
     );
 
@@ -214,7 +218,8 @@ router.post('/generate-token', authenticateToken, async (req: Request<{}, {}, Ge
       customPasswordLength: customPassword?.length
     });
 
-    if (!process.env.JWT_KEY) {
+    const jwtKey = secrets.get('JWT_KEY');
+    if (!jwtKey) {
       throw new AppError(500, 'JWT_KEY environment variable is not set');
     }
 
@@ -270,9 +275,10 @@ router.post('/generate-token', authenticateToken, async (req: Request<{}, {}, Ge
     // If no custom credentials provided, try to get from environment
     if (!username || !password) {
       try {
-        if (process.env.HOST_USERS) {
+        const hostUsers = secrets.get('HOST_USERS');
+        if (hostUsers) {
           // Remove any surrounding quotes that might be included in the env var
-          const cleanedHostUsers = process.env.HOST_USERS.replace(/^['"]|['"]$/g, '');
+          const cleanedHostUsers = hostUsers.replace(/^['"]|['"]$/g, '');
           logger.debug('Parsing HOST_USERS:', { cleanedHostUsers });
           
           const parsedUsers = JSON.parse(cleanedHostUsers);
@@ -319,12 +325,12 @@ router.post('/generate-token', authenticateToken, async (req: Request<{}, {}, Ge
 
     // Encrypt payload using AES
     const payloadString = JSON.stringify(tokenPayload);
-    const encryptedPayload = CryptoJS.AES.encrypt(payloadString, process.env.JWT_KEY).toString();
+    const encryptedPayload = CryptoJS.AES.encrypt(payloadString, jwtKey).toString();
 
     // Create JWT token
     const jwtToken = jwt.sign(
       { data: encryptedPayload },
-      process.env.JWT_KEY || '',
+      jwtKey,
       { expiresIn: finalExpireSeconds }
     );
 
@@ -375,9 +381,10 @@ router.get('/default-credentials', authenticateToken, async (_req: Request, res:
 
     // Try to get credentials from environment
     try {
-      if (process.env.HOST_USERS) {
+      const hostUsers = secrets.get('HOST_USERS');
+      if (hostUsers) {
         // Remove any surrounding quotes that might be included in the env var
-        const cleanedHostUsers = process.env.HOST_USERS.replace(/^['"]|['"]$/g, '');
+        const cleanedHostUsers = hostUsers.replace(/^['"]|['"]$/g, '');
         const parsedUsers = JSON.parse(cleanedHostUsers);
         if (parsedUsers && parsedUsers.length > 0) {
           defaultCredentials = {
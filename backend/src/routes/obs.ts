@@ -1,11 +1,12 @@
-import express, { Request, Response, NextFunction } from 'express';
+import { Request, Response, NextFunction } from 'express';
 import { body, validationResult } from 'express-validator';
 import { authenticateToken } from '../middleware/auth';
 import { AppError } from '../middleware/errorHandler';
 import { obsService } from '../services';
 import { logger } from '../utils/logger';
+import { asyncRouter } from '../utils/asyncRouter';
 
-const router = express.Router();
+const router = asyncRouter();
 
 // Require authentication for all OBS routes
 router.use(authenticateToken);
@@ -1,10 +1,11 @@
 import { Request, Response } from 'express';
-import express from 'express';
 import prisma from '../lib/prisma';
 import { logger } from '../utils/logger';
+import { asyncRouter } from '../utils/asyncRouter';
 // import crypto from 'crypto';
+// import { requireSecret } from '../config/secrets';
 
-const router = express.Router();
+const router = asyncRouter();
 
 /**
  * Generate a signature for stream authentication
@@ -12,7 +13,7 @@ const router = express.Router();
  */
 /*
 function generateSignature(streamKey: string): string {
-  const secret = process.env.OME_SIGNATURE_SECRET || 'colourstream-signature-key';
+  const secret = requireSecret('OME_SIGNATURE_SECRET');
   return crypto.createHmac('sha1', secret).update(streamKey).digest('hex');
 }
 */
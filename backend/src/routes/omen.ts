@@ -1,9 +1,9 @@
-import express from 'express';
 import { authenticateToken } from '../middleware/auth';
 import { omenService } from '../services/omenService';
 import { logger } from '../utils/logger';
+import { asyncRouter } from '../utils/asyncRouter';
 
-const router = express.Router();
+const router = asyncRouter();
 
 // Get all virtual hosts
 router.get('/vhosts', authenticateToken, async (_req, res, next) => {
@@ -1,8 +1,9 @@
 import express, { Request, Response, NextFunction } from 'express';
 // import { body, param } from 'express-validator';
 // import bcrypt from 'bcryptjs';
-// import { authenticateToken } from '../middleware/auth';
-import prisma from '../lib/prisma';
+import { authenticateToken } from '../middleware/auth';
+import { Prisma, Room } from '@prisma/client';
+import prisma, { prismaRead } from '../lib/prisma';
 import { AppError } from '../middleware/errorHandler';
 import { logger } from '../utils/logger';
 // import fetch from 'node-fetch';
@@ -10,17 +11,49 @@ import jwt from 'jsonwebtoken';
 import CryptoJS from 'crypto-js';
 import { RoomCreateInput } from '../types/room';
 import { generateUniqueId } from '../utils/idGenerator';
+import { expandRoomNamePattern, generateRoomName, isValidRoomName } from '../utils/roomNames';
+import { similarity } from '../utils/fuzzy';
+import { assertRoomNameAllowed } from '../utils/roomNameRules';
+import { assertSettingsSupported } from '../services/settingsValidator';
+import { fullTextRoomPage, roomNameFilter } from '../services/roomSearch';
+import { publishRoomEvent } from '../services/roomEvents';
 import rateLimit from 'express-rate-limit';
+import { roomConfig } from '../config/roomConfig';
+import { rateLimitHeaders } from '../middleware/security';
+import { secrets } from '../config/secrets';
+import { queryInt } from '../utils/queryParams';
+import { buildRoomFeed } from '../utils/atomFeed';
+import { applyMergePatch } from '../utils/mergePatch';
+import { unavailableReason } from '../utils/roomSchedule';
+import { formatDuration, parseDuration } from '../utils/duration';
+import { shareLinkService } from '../services/shareLinks';
+import { qrCodePng, qrCodeSvg } from '../utils/qrCode';
+import { encodeRoom, encodeRoomList, PROTOBUF_MEDIA_TYPE } from '../utils/roomProtobuf';
+import { asyncRouter } from '../utils/asyncRouter';
 
-const router = express.Router();
+const router = asyncRouter();
+
+// Room data changes constantly; make sure browsers and proxies never serve a stale copy
+router.use((_req, res, next) => {
+  res.set('Cache-Control', 'no-store');
+  next();
+});
 
 // Special rate limiter for room validation - higher limits than general
 const roomValidationLimiter = rateLimit({
   windowMs: 5 * 60 * 1000, // 5 minutes
   max: 50, // Allow 50 validation attempts per 5 minutes
   message: 'Too many room validation attempts, please try again later',
-  standardHeaders: true,
-  legacyHeaders: false,
+  ...rateLimitHeaders,
+});
+
+// Per-room limiter for joins, so a single room cannot be flooded regardless of client IP
+const roomJoinLimiter = rateLimit({
+  windowMs: 1000,
+  max: roomConfig.joinRateLimitPerSecond,
+  message: { status: 'error', message: 'Too many join attempts for this room, please try again shortly' },
+  ...rateLimitHeaders,
+  keyGenerator: (req) => `room:${req.params.id}`,
 });
 
 // These are for documentation purposes only
@@ -48,13 +81,12 @@ const generateMiroTalkToken = async (
   customPassword?: string
 ): Promise<string> => {
   try {
-    if (!process.env.JWT_KEY) {
+    // Use the standard JWT_KEY
+    const JWT_KEY = secrets.get('JWT_KEY');
+    if (!JWT_KEY) {
       throw new Error('JWT_KEY environment variable is not set');
     }
     
-    // Use the standard JWT_KEY
-    const JWT_KEY = process.env.JWT_KEY;
-    
     // Log the incoming parameters for debugging
     logger.debug('generateMiroTalkToken called with params:', {
       roomId,
@@ -68,9 +100,10 @@ const generateMiroTalkToken = async (
     
     // Try to get default credentials from HOST_USERS environment variable
     try {
-      if (process.env.HOST_USERS) {
+      const hostUsers = secrets.get('HOST_USERS');
+      if (hostUsers) {
         // Remove any surrounding quotes that might be included in the env var
-        const cleanedHostUsers = process.env.HOST_USERS.replace(/^['"]|['"]$/g, '');
+        const cleanedHostUsers = hostUsers.replace(/^['"]|['"]$/g, '');
         logger.debug('Parsing HOST_USERS:', { cleanedHostUsers });
         
         const parsedUsers = JSON.parse(cleanedHostUsers);
@@ -145,13 +178,157 @@ const generateMiroTalkToken = async (
   }
 };
 
+const RFC3339_PATTERN = /^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})$/i;
+
+// Parse an optional RFC3339 timestamp query parameter, throwing a 400 when malformed
+const parseTimestampParam = (value: unknown, name: string): Date | undefined => {
+  if (value === undefined || value === '') {
+    return undefined;
+  }
+  if (typeof value !== 'string' || !RFC3339_PATTERN.test(value) || isNaN(Date.parse(value))) {
+    throw new AppError(400, `Invalid ${name}: expected an RFC3339 timestamp such as 2025-01-31T12:00:00Z`);
+  }
+  return new Date(value);
+};
+
+// Parse the comma-separated ids query parameter used for batch lookups
+const parseIdsParam = (value: unknown): string[] | undefined => {
+  if (value === undefined) {
+    return undefined;
+  }
+  if (typeof value !== 'string') {
+    throw new AppError(400, 'Invalid ids: expected a comma-separated list');
+  }
+
+  const ids = Array.from(new Set(value.split(',').map(id => id.trim()).filter(Boolean)));
+  if (ids.length === 0) {
+    throw new AppError(400, 'Invalid ids: expected at least one room id');
+  }
+  if (ids.length > roomConfig.maxBatchIds) {
+    throw new AppError(400, `Too many ids: at most ${roomConfig.maxBatchIds} rooms can be fetched per request`);
+  }
+  return ids;
+};
+
+interface PageParams {
+  sinceId?: string;
+  limit: number;
+}
+
+// Parse keyset pagination parameters; paging only applies when since_id or limit is given
+const parsePageParams = (req: Request): PageParams | undefined => {
+  const { since_id: sinceId, limit } = req.query;
+  if (sinceId === undefined && limit === undefined) {
+    return undefined;
+  }
+  if (sinceId !== undefined && (typeof sinceId !== 'string' || sinceId === '')) {
+    throw new AppError(400, 'Invalid since_id: expected a room id');
+  }
+
+  // Rejecting by default means a client never mistakes a clamped page for the one it asked for
+  const pageSize = queryInt(req, 'limit', Math.min(roomConfig.defaultPageSize, roomConfig.maxPageSize), {
+    min: 1,
+    max: roomConfig.maxPageSize,
+    clamp: roomConfig.pageSizeOverflow === 'clamp',
+  });
+
+  return { sinceId: sinceId as string | undefined, limit: pageSize };
+};
+
+// Shape the room-list envelope for the negotiated API version (see middleware/apiVersion)
+interface RoomListExtras {
+  missing?: string[];
+  nextCursor?: string | null;
+}
+
+// Clients such as the media server can ask for protobuf (proto/room.proto) instead of JSON
+const wantsProtobuf = (req: Request): boolean =>
+  req.accepts(['application/json', PROTOBUF_MEDIA_TYPE]) === PROTOBUF_MEDIA_TYPE;
+
+const sendRoomList = (req: Request, res: Response, rooms: Partial<Room>[], extras: RoomListExtras = {}) => {
+  if (wantsProtobuf(req)) {
+    return res.status(200).type(PROTOBUF_MEDIA_TYPE).send(encodeRoomList(rooms, extras));
+  }
+  // Echo the negotiated media type so clients can confirm which contract they got
+  if (req.apiMediaType) {
+    res.type(req.apiMediaType);
+  }
+  switch (req.apiVersion) {
+    case 1:
+    default:
+      return res.status(200).json({
+        status: 'success',
+        data: { rooms, ...extras },
+      });
+  }
+};
+
 // Get all rooms
-router.get("/", async (_req: Request, res: Response) => {
+router.get("/", async (req: Request, res: Response) => {
   try {
-    const rooms = await prisma.room.findMany({
-      orderBy: {
-        createdAt: 'desc',
-      },
+    const createdAfter = parseTimestampParam(req.query.created_after, 'created_after');
+    const createdBefore = parseTimestampParam(req.query.created_before, 'created_before');
+
+    const ids = parseIdsParam(req.query.ids);
+
+    const where: Prisma.RoomWhereInput = {};
+    if (createdAfter || createdBefore) {
+      where.createdAt = {
+        ...(createdAfter && { gte: createdAfter }),
+        ...(createdBefore && { lte: createdBefore }),
+      };
+    }
+    if (ids) {
+      where.id = { in: ids };
+    }
+
+    // Plain name filter; fuzzy ranking lives at /rooms/search
+    const { q } = req.query;
+    if (q !== undefined && (typeof q !== 'string' || q.trim() === '')) {
+      throw new AppError(400, 'Invalid q: expected a non-empty search string');
+    }
+    const search = typeof q === 'string' ? q.trim() : undefined;
+
+    // Keyset pagination: WHERE id > since_id ORDER BY id LIMIT n, stable under concurrent inserts
+    const page = parsePageParams(req);
+    const orderBy: Prisma.RoomOrderByWithRelationInput = page ? { id: 'asc' } : { createdAt: 'desc' };
+
+    // format=ids returns a bare array of matching room ids for automation
+    const { format } = req.query;
+    if (format !== undefined && format !== 'ids') {
+      throw new AppError(400, 'Invalid format: only "ids" is supported');
+    }
+
+    // With ROOM_SEARCH_FTS, one raw query applies the name match, filters and cursor and returns this page's ids
+    const fullText = search ? await fullTextRoomPage(search, { createdAfter, createdBefore, ids }, page) : null;
+    if (fullText) {
+      where.id = { in: fullText.ids };
+      res.set('X-Total-Count', String(fullText.total));
+    } else {
+      if (search) {
+        where.AND = [roomNameFilter(search)];
+      }
+      // X-Total-Count reports every room matching the filters, so count before applying the cursor
+      res.set('X-Total-Count', String(await prismaRead.room.count({ where })));
+      if (page?.sinceId) {
+        where.id = { ...(ids && { in: ids }), gt: page.sinceId };
+      }
+    }
+
+    if (format === 'ids') {
+      const matches = await prismaRead.room.findMany({
+        where,
+        orderBy,
+        take: page?.limit,
+        select: { id: true },
+      });
+      return res.status(200).json(matches.map(room => room.id));
+    }
+
+    const rooms = await prismaRead.room.findMany({
+      where,
+      orderBy,
+      take: page?.limit,
       select: {
         id: true,
         name: true,
@@ -163,19 +340,131 @@ router.get("/", async (_req: Request, res: Response) => {
         link: true,
         presenterLink: true,
         mirotalkToken: true,
+        availableFrom: true,
+        availableUntil: true,
         createdAt: true
       }
     });
-    return res.status(200).json({
-      status: 'success',
-      data: { rooms }
-    });
+
+    const extras: RoomListExtras = {};
+
+    // For batch lookups, report which requested ids did not match a room
+    if (ids) {
+      const found = new Set(rooms.map(room => room.id));
+      extras.missing = ids.filter(id => !found.has(id));
+    }
+
+    // A full page means there may be more; the last id is the cursor for the next one
+    if (page) {
+      extras.nextCursor = rooms.length === page.limit ? rooms[rooms.length - 1].id : null;
+    }
+
+    return sendRoomList(req, res, rooms, extras);
   } catch (error) {
+    if (error instanceof AppError) {
+      return res.status(error.statusCode).json({ status: 'error', message: error.message });
+    }
     console.error("Error fetching rooms:", error);
     return res.status(500).json({ error: "Failed to fetch rooms" });
   }
 });
 
+interface RoomSchedule {
+  availableFrom?: Date;
+  availableUntil?: Date;
+}
+
+// Parse the optional join window of a new room from the request body
+const parseRoomSchedule = (body: { availableFrom?: unknown; availableUntil?: unknown }): RoomSchedule => {
+  const availableFrom = parseTimestampParam(body.availableFrom ?? undefined, 'availableFrom');
+  const availableUntil = parseTimestampParam(body.availableUntil ?? undefined, 'availableUntil');
+  if (availableFrom && availableUntil && availableFrom >= availableUntil) {
+    throw new AppError(400, 'availableFrom must be before availableUntil');
+  }
+  return { availableFrom, availableUntil };
+};
+
+// Build the data for a new room: ids, links and a MiroTalk token that expires with the room
+const buildRoomData = async (name: string, password: string, expiryDays: number, schedule: RoomSchedule = {}): Promise<RoomCreateInput> => {
+  // Calculate expiry date from expiryDays
+  const expiryDate = new Date();
+  expiryDate.setDate(expiryDate.getDate() + Number(expiryDays));
+
+  // Generate unique IDs and tokens
+  const mirotalkRoomId = generateUniqueId();
+  const streamKey = generateUniqueId();
+  const displayPassword = password.substring(0, 3) + "***";
+  
+  // Generate room ID
+  const roomId = generateUniqueId();
+  
+  // Generate links using the room ID
+  const link = `${process.env.FRONTEND_URL}/room/${roomId}`;
+  const presenterLink = `${process.env.FRONTEND_URL}/room/${roomId}?access=p`;
+  
+  // Generate MiroTalk token that expires at the same time as the room
+  // Always use default credentials from HOST_USERS
+  let mirotalkToken;
+  try {
+    mirotalkToken = await generateMiroTalkToken(
+      mirotalkRoomId, 
+      Number(expiryDays),
+      undefined,  // Force using default username from HOST_USERS
+      undefined   // Force using default password from HOST_USERS
+    );
+    logger.info('Generated MiroTalk token for room using default credentials', { 
+      roomId, 
+      mirotalkRoomId
+    });
+  } catch (error) {
+    logger.error('Failed to generate MiroTalk token', { error });
+    // Continue without token if generation fails
+  }
+
+  return {
+    id: roomId, // Use the generated ID
+    name,
+    mirotalkRoomId,
+    streamKey,
+    password,
+    displayPassword,
+    expiryDate,
+    link,
+    presenterLink,
+    mirotalkToken, // Always include the token
+    ...schedule,
+  };
+};
+
+// Return the oldest room with this name, creating one if none exists.
+// Concurrent calls for the same name are serialized so they converge on one room.
+const findOrCreateRoomByName = async (name: string, password?: string, expiryDays?: number, schedule: RoomSchedule = {}) => {
+  const result = await prisma.$transaction(async (tx) => {
+    await tx.$executeRaw`SELECT pg_advisory_xact_lock(hashtext(${name}))`;
+
+    const existing = await tx.room.findFirst({
+      where: { name },
+      orderBy: { createdAt: 'asc' },
+    });
+    if (existing) {
+      return { room: existing, created: false };
+    }
+
+    if (!password || !expiryDays) {
+      throw new AppError(400, 'Password and expiryDays are required to create a room');
+    }
+
+    const roomData = await buildRoomData(name, password, Number(expiryDays), schedule);
+    const room = await tx.room.create({ data: roomData });
+    return { room, created: true };
+  });
+
+  if (result.created) {
+    publishRoomEvent({ type: 'room.created', roomId: result.room.id, name: result.room.name });
+  }
+  return result;
+};
+
 // Create a new room
 router.post("/", async (req: Request, res: Response) => {
   try {
@@ -189,77 +478,330 @@ router.post("/", async (req: Request, res: Response) => {
     });
 
     // Validate required fields
-    if (!name || !password || !expiryDays) {
+    if (!password || !expiryDays) {
       return res.status(400).json({ error: "Missing required fields" });
     }
 
-    // Calculate expiry date from expiryDays
-    const expiryDate = new Date();
-    expiryDate.setDate(expiryDate.getDate() + Number(expiryDays));
+    if (name) {
+      assertRoomNameAllowed(name);
+    }
+    // Validated up front so a malformed window is a 400 even when get_existing finds a room
+    const schedule = parseRoomSchedule(req.body);
 
-    // Generate unique IDs and tokens
-    const mirotalkRoomId = generateUniqueId();
-    const streamKey = generateUniqueId();
-    const displayPassword = password.substring(0, 3) + "***";
-    
-    // Generate room ID
-    const roomId = generateUniqueId();
-    
-    // Generate links using the room ID
-    const link = `${process.env.FRONTEND_URL}/room/${roomId}`;
-    const presenterLink = `${process.env.FRONTEND_URL}/room/${roomId}?access=p`;
-    
-    // Generate MiroTalk token that expires at the same time as the room
-    // Always use default credentials from HOST_USERS
-    let mirotalkToken;
-    try {
-      mirotalkToken = await generateMiroTalkToken(
-        mirotalkRoomId, 
-        Number(expiryDays),
-        undefined,  // Force using default username from HOST_USERS
-        undefined   // Force using default password from HOST_USERS
-      );
-      logger.info('Generated MiroTalk token for room using default credentials', { 
-        roomId, 
-        mirotalkRoomId
+    // Opt-in "create or get": reuse an existing room with the same name
+    if (name && req.query.get_existing === 'true') {
+      const result = await findOrCreateRoomByName(name, password, Number(expiryDays), schedule);
+      return res.status(result.created ? 201 : 200).json({
+        status: 'success',
+        data: result
       });
-    } catch (error) {
-      logger.error('Failed to generate MiroTalk token', { error });
-      // Continue without token if generation fails
     }
 
+    // Fall back to the configured name template when no name is given
+    const roomName = name || await generateRoomName();
+
     // Create room data
-    const roomData: RoomCreateInput = {
-      id: roomId, // Use the generated ID
-      name,
-      mirotalkRoomId,
-      streamKey,
-      password,
-      displayPassword,
-      expiryDate,
-      link,
-      presenterLink,
-      mirotalkToken, // Always include the token
-    };
+    const roomData = await buildRoomData(roomName, password, Number(expiryDays), schedule);
     
     const room = await prisma.room.create({
       data: roomData,
     });
+    publishRoomEvent({ type: 'room.created', roomId: room.id, name: room.name });
     
     return res.status(201).json({
       status: 'success',
       data: { room }
     });
   } catch (error) {
+    if (error instanceof AppError) {
+      return res.status(error.statusCode).json({ status: 'error', message: error.message });
+    }
     console.error("Error creating room:", error);
     return res.status(500).json({ error: "Failed to create room" });
   }
 });
 
+// Create many rooms at once from a name pattern, e.g. breakout-{seq}. All or nothing:
+// if any room fails, the whole batch (including the {seq} values it drew) is rolled back.
+router.post("/bulk-create", authenticateToken, async (req: Request, res: Response) => {
+  try {
+    const { count, namePattern, password, expiryDays } = req.body;
+
+    if (!Number.isInteger(count) || count < 1 || count > roomConfig.bulkCreateMax) {
+      throw new AppError(400, `count must be an integer between 1 and ${roomConfig.bulkCreateMax}`);
+    }
+    if (typeof namePattern !== 'string' || namePattern.trim() === '') {
+      throw new AppError(400, 'namePattern is required');
+    }
+    if (count > 1 && !/\{(seq|rand)\}/.test(namePattern)) {
+      throw new AppError(400, 'namePattern must contain {seq} or {rand} to create more than one room');
+    }
+    if (!password || !expiryDays) {
+      throw new AppError(400, 'Password and expiryDays are required');
+    }
+
+    const rooms = await prisma.$transaction(async (tx) => {
+      const created = [];
+      for (let i = 0; i < count; i++) {
+        const name = await expandRoomNamePattern(namePattern, tx);
+        if (!isValidRoomName(name)) {
+          throw new AppError(400, `namePattern produced an invalid room name "${name}"`);
+        }
+        assertRoomNameAllowed(name);
+        created.push(await tx.room.create({ data: await buildRoomData(name, password, Number(expiryDays)) }));
+      }
+      return created;
+    }, { timeout: 30000 });
+
+    rooms.forEach(room => publishRoomEvent({ type: 'room.created', roomId: room.id, name: room.name }));
+    logger.info('Bulk-created rooms', { count: rooms.length, namePattern });
+
+    return res.status(201).json({
+      status: 'success',
+      data: { rooms }
+    });
+  } catch (error) {
+    if (error instanceof AppError) {
+      return res.status(error.statusCode).json({ status: 'error', message: error.message });
+    }
+    console.error("Error bulk-creating rooms:", error);
+    return res.status(500).json({ status: 'error', message: "Failed to create rooms" });
+  }
+});
+
+// Ensure a room with the given name exists, creating it if absent
+router.put("/by-name/:name", authenticateToken, async (req: Request, res: Response) => {
+  try {
+    const { name } = req.params;
+    const { password, expiryDays } = req.body;
+
+    assertRoomNameAllowed(name);
+    const result = await findOrCreateRoomByName(name, password, expiryDays);
+
+    if (result.created) {
+      logger.info('Ensured room by creating it', { roomId: result.room.id, name });
+    }
+
+    return res.status(result.created ? 201 : 200).json({
+      status: 'success',
+      data: result
+    });
+  } catch (error) {
+    if (error instanceof AppError) {
+      return res.status(error.statusCode).json({
+        status: 'error',
+        message: error.message
+      });
+    }
+    console.error("Error ensuring room:", error);
+    return res.status(500).json({
+      status: 'error',
+      message: "Failed to ensure room"
+    });
+  }
+});
+
+interface RoomDiff {
+  toCreate: string[];
+  toDelete: string[];
+}
+
+// Names to create and rooms to delete so that the existing room names match the desired set
+const diffRoomNames = (desired: string[], existing: { name: string }[]): RoomDiff => {
+  const existingNames = new Set(existing.map(room => room.name));
+  const desiredNames = new Set(desired);
+  return {
+    toCreate: desired.filter(name => !existingNames.has(name)),
+    toDelete: Array.from(existingNames).filter(name => !desiredNames.has(name)),
+  };
+};
+
+// Diff the desired room names against the current rooms; with apply=true, perform the changes atomically.
+// Deleting more than ROOM_RECONCILE_CONFIRM_DELETES rooms also needs confirmDeletes set to that count,
+// so a truncated or mistyped desired list can't wipe out the room list.
+router.post("/reconcile", authenticateToken, async (req: Request, res: Response) => {
+  try {
+    const { desired, password, expiryDays, confirmDeletes } = req.body || {};
+    if (!Array.isArray(desired) || desired.some(name => typeof name !== 'string')) {
+      throw new AppError(400, 'desired must be an array of room names');
+    }
+    const desiredNames = Array.from(new Set(desired.map((name: string) => name.trim())));
+    desiredNames.forEach(name => assertRoomNameAllowed(name));
+
+    if (req.query.apply !== 'true') {
+      const existing = await prisma.room.findMany({ select: { name: true } });
+      return res.status(200).json({
+        status: 'success',
+        data: { ...diffRoomNames(desiredNames, existing), applied: false }
+      });
+    }
+
+    const result = await prisma.$transaction(async (tx) => {
+      // Take the same per-name locks as find-or-create (sorted to avoid deadlocks) so neither duplicates a room
+      for (const name of [...desiredNames].sort()) {
+        await tx.$executeRaw`SELECT pg_advisory_xact_lock(hashtext(${name}))`;
+      }
+
+      const existing = await tx.room.findMany({ select: { name: true } });
+      const diff = diffRoomNames(desiredNames, existing);
+      if (diff.toDelete.length > roomConfig.reconcileConfirmDeletes && confirmDeletes !== diff.toDelete.length) {
+        throw new AppError(409, `Reconcile would delete ${diff.toDelete.length} rooms; resend with confirmDeletes: ${diff.toDelete.length} to proceed`);
+      }
+      if (diff.toCreate.length > 0 && (!password || !expiryDays)) {
+        throw new AppError(400, 'Password and expiryDays are required to create rooms');
+      }
+
+      const created = [];
+      for (const name of diff.toCreate) {
+        created.push(await tx.room.create({ data: await buildRoomData(name, password, Number(expiryDays)) }));
+      }
+      const deleted = await tx.room.findMany({
+        where: { name: { in: diff.toDelete } },
+        select: { id: true, name: true },
+      });
+      await tx.room.deleteMany({ where: { id: { in: deleted.map(room => room.id) } } });
+
+      return { diff, created, deleted };
+    });
+
+    result.created.forEach(room => publishRoomEvent({ type: 'room.created', roomId: room.id, name: room.name }));
+    result.deleted.forEach(room => publishRoomEvent({ type: 'room.deleted', roomId: room.id, name: room.name }));
+    logger.info('Reconciled rooms', { created: result.created.length, deleted: result.deleted.length });
+
+    return res.status(200).json({
+      status: 'success',
+      data: { ...result.diff, applied: true }
+    });
+  } catch (error) {
+    if (error instanceof AppError) {
+      return res.status(error.statusCode).json({ status: 'error', message: error.message });
+    }
+    console.error("Error reconciling rooms:", error);
+    return res.status(500).json({ status: 'error', message: "Failed to reconcile rooms" });
+  }
+});
+
+// Most recently created rooms as an Atom feed, for status pages and feed readers
+router.get("/feed.atom", async (req: Request, res: Response) => {
+  try {
+    const limit = queryInt(req, 'limit', roomConfig.feedSize, { min: 1, max: roomConfig.maxPageSize, clamp: true });
+    const rooms = await prismaRead.room.findMany({
+      orderBy: { createdAt: 'desc' },
+      take: limit,
+      select: { id: true, name: true, link: true, expiryDate: true, createdAt: true },
+    });
+
+    const feedUrl = `${req.protocol}://${req.get('host')}${req.originalUrl.split('?')[0]}`;
+    // Unlike the JSON routes, a feed that is a minute old is fine; Express adds an ETag for revalidation
+    res.set('Cache-Control', 'private, max-age=60');
+    return res.type('application/atom+xml').send(buildRoomFeed(rooms, feedUrl));
+  } catch (error) {
+    if (error instanceof AppError) {
+      return res.status(error.statusCode).json({ status: 'error', message: error.message });
+    }
+    console.error("Error building room feed:", error);
+    return res.status(500).json({ error: "Failed to build room feed" });
+  }
+});
+
+// Fuzzy search rooms by name, best matches first
+router.get("/search", async (req: Request, res: Response) => {
+  try {
+    const { q } = req.query;
+    if (typeof q !== 'string' || q.trim() === '') {
+      throw new AppError(400, 'Query parameter q is required');
+    }
+
+    const limit = queryInt(req, 'limit', roomConfig.searchResultLimit, { min: 1, max: roomConfig.searchResultLimit });
+
+    // Names are short and the room table is small, so rank the candidates in process
+    const candidates = await prismaRead.room.findMany({
+      select: {
+        id: true,
+        name: true,
+        link: true,
+        expiryDate: true,
+        createdAt: true
+      }
+    });
+
+    const results = candidates
+      .map(room => ({ room, score: Number(similarity(q, room.name).toFixed(3)) }))
+      .filter(result => result.score >= roomConfig.searchMinScore)
+      .sort((a, b) => b.score - a.score || b.room.createdAt.getTime() - a.room.createdAt.getTime())
+      .slice(0, limit);
+
+    return res.status(200).json({
+      status: 'success',
+      data: { results }
+    });
+  } catch (error) {
+    if (error instanceof AppError) {
+      return res.status(error.statusCode).json({ status: 'error', message: error.message });
+    }
+    console.error("Error searching rooms:", error);
+    return res.status(500).json({ error: "Failed to search rooms" });
+  }
+});
+
+const isPlainObject = (value: unknown): value is Record<string, unknown> =>
+  typeof value === 'object' && value !== null && !Array.isArray(value);
+
+// Rooms bucketed by the value of one settings key; rooms without a scalar value for it are "ungrouped"
+router.get("/grouped", async (req: Request, res: Response) => {
+  try {
+    const { by } = req.query;
+    if (typeof by !== 'string' || by.trim() === '') {
+      throw new AppError(400, 'Query parameter by is required');
+    }
+    if (!roomConfig.groupByKeys.includes(by)) {
+      throw new AppError(400, `Cannot group by "${by}"; expected one of ${roomConfig.groupByKeys.join(', ')}`);
+    }
+
+    const rooms = await prismaRead.room.findMany({
+      orderBy: { createdAt: 'desc' },
+      select: { id: true, name: true, link: true, expiryDate: true, createdAt: true, settings: true },
+    });
+
+    // No prototype, so a settings value such as "__proto__" is just another group name
+    const groups: Record<string, typeof rooms> = Object.create(null);
+    const ungrouped: typeof rooms = [];
+    for (const room of rooms) {
+      const value = isPlainObject(room.settings) ? room.settings[by] : undefined;
+      if (typeof value === 'string' || typeof value === 'number' || typeof value === 'boolean') {
+        const group = String(value);
+        (groups[group] = groups[group] || []).push(room);
+      } else {
+        ungrouped.push(room);
+      }
+    }
+
+    return res.status(200).json({
+      status: 'success',
+      data: { by, groups, ungrouped }
+    });
+  } catch (error) {
+    if (error instanceof AppError) {
+      return res.status(error.statusCode).json({ status: 'error', message: error.message });
+    }
+    console.error("Error grouping rooms:", error);
+    return res.status(500).json({ error: "Failed to group rooms" });
+  }
+});
+
+// Strong ETag derived from the room's version counter
+const roomETag = (room: { version: number }): string => `"${room.version}"`;
+
+// If-Match uses strong comparison, so weak (W/) tags never match; "*" matches any existing room
+const ifMatchAllows = (header: string, etag: string): boolean =>
+  header.split(',').map(tag => tag.trim()).some(tag => tag === '*' || tag === etag);
+
 // Get a specific room
 router.get("/:id", async (req: Request, res: Response) => {
   try {
     const { id } = req.params;
+    // The primary, not the replica: its version is served as the ETag PATCH compares If-Match with,
+    // and a lagging replica would hand out a stale one right after a write
     const room = await prisma.room.findUnique({
       where: {
         id: String(id),
@@ -270,6 +812,11 @@ router.get("/:id", async (req: Request, res: Response) => {
       return res.status(404).json({ error: "Room not found" });
     }
 
+    // Clients echo this back in If-Match when they PATCH the room
+    res.set('ETag', roomETag(room));
+    if (wantsProtobuf(req)) {
+      return res.status(200).type(PROTOBUF_MEDIA_TYPE).send(encodeRoom(room));
+    }
     return res.status(200).json(room);
   } catch (error) {
     console.error("Error fetching room:", error);
@@ -277,6 +824,257 @@ router.get("/:id", async (req: Request, res: Response) => {
   }
 });
 
+const MERGE_PATCH_TYPE = 'application/merge-patch+json';
+const PATCHABLE_FIELDS = ['name', 'settings'];
+
+// Partially update a room with an RFC 7386 JSON Merge Patch: omitted fields are left alone,
+// null clears a field, and the merged room is validated before it is saved. The If-Match header
+// must carry the ETag from GET /rooms/:id, so an edit made since that read isn't silently overwritten.
+router.patch("/:id", authenticateToken, express.json({ type: MERGE_PATCH_TYPE }), async (req: Request, res: Response) => {
+  try {
+    const ifMatch = req.get('If-Match');
+    if (!ifMatch) {
+      throw new AppError(428, 'If-Match header is required; send the ETag from GET /rooms/:id');
+    }
+    if (!req.is(MERGE_PATCH_TYPE)) {
+      throw new AppError(415, `Expected Content-Type: ${MERGE_PATCH_TYPE}`);
+    }
+    if (!isPlainObject(req.body)) {
+      throw new AppError(400, 'Merge patch must be a JSON object');
+    }
+    const unsupported = Object.keys(req.body).filter(field => !PATCHABLE_FIELDS.includes(field));
+    if (unsupported.length > 0) {
+      throw new AppError(400, `Only ${PATCHABLE_FIELDS.join(' and ')} can be patched, got: ${unsupported.join(', ')}`);
+    }
+    // Checked before the transaction, which shouldn't stay open while capabilities are fetched
+    if (isPlainObject(req.body.settings)) {
+      await assertSettingsSupported(req.body.settings);
+    }
+
+    const id = String(req.params.id);
+    const { previousName, room } = await prisma.$transaction(async (tx) => {
+      const current = await tx.room.findUnique({ where: { id }, select: { name: true, settings: true, version: true } });
+      if (!current) {
+        throw new AppError(404, 'Room not found');
+      }
+      if (!ifMatchAllows(ifMatch, roomETag(current))) {
+        throw new AppError(412, 'Room has changed since it was read; fetch it again and retry');
+      }
+
+      const document = { name: current.name, ...(current.settings !== null && { settings: current.settings }) };
+      const merged = applyMergePatch(document, req.body) as { name?: unknown; settings?: unknown };
+
+      if (typeof merged.name !== 'string') {
+        throw new AppError(422, 'Room name is required and must be a string');
+      }
+      assertRoomNameAllowed(merged.name);
+      if (merged.settings !== undefined && !isPlainObject(merged.settings)) {
+        throw new AppError(422, 'settings must be a JSON object');
+      }
+
+      // Matching on the version as well catches a concurrent update that committed after our read
+      const { count } = await tx.room.updateMany({
+        where: { id, version: current.version },
+        data: {
+          name: merged.name,
+          settings: merged.settings === undefined ? Prisma.DbNull : merged.settings as Prisma.InputJsonObject,
+          version: { increment: 1 },
+        },
+      });
+      if (count === 0) {
+        throw new AppError(412, 'Room has changed since it was read; fetch it again and retry');
+      }
+      const updated = await tx.room.findUniqueOrThrow({ where: { id } });
+      return { previousName: current.name, room: updated };
+    });
+
+    if (room.name !== previousName) {
+      publishRoomEvent({ type: 'room.renamed', roomId: room.id, name: room.name, previousName });
+    }
+
+    res.set('ETag', roomETag(room));
+    return res.status(200).json({
+      status: 'success',
+      data: { room }
+    });
+  } catch (error) {
+    if (error instanceof AppError) {
+      return res.status(error.statusCode).json({ status: 'error', message: error.message });
+    }
+    console.error("Error patching room:", error);
+    return res.status(500).json({ status: 'error', message: "Failed to update room" });
+  }
+});
+
+// Get just a room's settings, for clients such as the media server that don't need the whole record
+router.get("/:id/settings", async (req: Request, res: Response) => {
+  try {
+    // From the primary, like GET /rooms/:id, because the version goes out as the ETag
+    const room = await prisma.room.findUnique({
+      where: { id: String(req.params.id) },
+      select: { settings: true, version: true },
+    });
+
+    if (!room) {
+      return res.status(404).json({ error: "Room not found" });
+    }
+
+    // Same room ETag as GET /rooms/:id, for If-Match on PATCH /rooms/:id/settings
+    res.set('ETag', roomETag(room));
+    return res.status(200).json(isPlainObject(room.settings) ? room.settings : {});
+  } catch (error) {
+    console.error("Error fetching room settings:", error);
+    return res.status(500).json({ error: "Failed to fetch room settings" });
+  }
+});
+
+// Update a room's settings: keys in the body are set, keys set to null are removed. Like PATCH /rooms/:id,
+// If-Match must carry the room's current ETag.
+router.patch("/:id/settings", authenticateToken, async (req: Request, res: Response) => {
+  try {
+    const ifMatch = req.get('If-Match');
+    if (!ifMatch) {
+      throw new AppError(428, 'If-Match header is required; send the ETag from GET /rooms/:id/settings');
+    }
+    if (!isPlainObject(req.body)) {
+      throw new AppError(400, 'Settings must be a JSON object');
+    }
+    await assertSettingsSupported(req.body);
+
+    const id = String(req.params.id);
+    const { settings, version } = await prisma.$transaction(async (tx) => {
+      const room = await tx.room.findUnique({
+        where: { id },
+        select: { settings: true, version: true },
+      });
+      if (!room) {
+        throw new AppError(404, 'Room not found');
+      }
+      if (!ifMatchAllows(ifMatch, roomETag(room))) {
+        throw new AppError(412, 'Room has changed since it was read; fetch it again and retry');
+      }
+
+      const merged: Record<string, unknown> = isPlainObject(room.settings) ? { ...room.settings } : {};
+      for (const [key, value] of Object.entries(req.body)) {
+        if (value === null) {
+          delete merged[key];
+        } else {
+          merged[key] = value;
+        }
+      }
+
+      const { count } = await tx.room.updateMany({
+        where: { id, version: room.version },
+        data: { settings: merged as Prisma.InputJsonObject, version: { increment: 1 } },
+      });
+      if (count === 0) {
+        throw new AppError(412, 'Room has changed since it was read; fetch it again and retry');
+      }
+      return { settings: merged, version: room.version + 1 };
+    });
+
+    res.set('ETag', roomETag({ version }));
+    return res.status(200).json(settings);
+  } catch (error) {
+    if (error instanceof AppError) {
+      return res.status(error.statusCode).json({ error: error.message });
+    }
+    console.error("Error updating room settings:", error);
+    return res.status(500).json({ error: "Failed to update room settings" });
+  }
+});
+
+const QR_CODE_FORMATS = ['png', 'svg'];
+// Images are rendered and compressed on the event loop, and the route is public, so keep them small
+const QR_CODE_MAX_SIZE = 1024;
+
+const roomJoinUrl = (room: { id: string; mirotalkRoomId: string; link: string }): string =>
+  roomConfig.joinUrlTemplate
+    ? roomConfig.joinUrlTemplate.replace(/\{(id|mirotalkRoomId)\}/g, (_match, key: 'id' | 'mirotalkRoomId') => encodeURIComponent(room[key]))
+    : room.link;
+
+// Render the room's join URL as a QR code, ?format=png (default) or svg, ?size= pixels square
+router.get("/:id/qrcode", async (req: Request, res: Response) => {
+  try {
+    const format = req.query.format === undefined ? 'png' : req.query.format;
+    if (typeof format !== 'string' || !QR_CODE_FORMATS.includes(format)) {
+      throw new AppError(400, `Invalid format: expected one of ${QR_CODE_FORMATS.join(', ')}`);
+    }
+    const size = queryInt(req, 'size', 256, { min: 64, max: QR_CODE_MAX_SIZE });
+
+    const room = await prisma.room.findUnique({
+      where: { id: String(req.params.id) },
+      select: { id: true, mirotalkRoomId: true, link: true },
+    });
+    if (!room) {
+      throw new AppError(404, 'Room not found');
+    }
+
+    const url = roomJoinUrl(room);
+    res.set('Cache-Control', 'private, max-age=300');
+    if (format === 'svg') {
+      return res.type('image/svg+xml').send(qrCodeSvg(url, size));
+    }
+    return res.type('image/png').send(qrCodePng(url, size));
+  } catch (error) {
+    if (error instanceof AppError) {
+      return res.status(error.statusCode).json({ error: error.message });
+    }
+    console.error("Error rendering room QR code:", error);
+    return res.status(500).json({ error: "Failed to render room QR code" });
+  }
+});
+
+// Create a signed guest link to a room, valid for expiresIn (default SHARE_LINK_TTL) or until the room expires
+router.post("/:id/share-link", authenticateToken, async (req: Request, res: Response) => {
+  try {
+    const { expiresIn, oneTime = false } = req.body || {};
+    if (typeof oneTime !== 'boolean') {
+      throw new AppError(400, 'oneTime must be a boolean');
+    }
+
+    let ttlMs = roomConfig.shareLinkTtlMs;
+    if (expiresIn !== undefined) {
+      const requested = typeof expiresIn === 'string' ? parseDuration(expiresIn) : null;
+      if (requested === null || requested <= 0 || requested > roomConfig.shareLinkMaxTtlMs) {
+        throw new AppError(400, `expiresIn must be a duration such as 2h, at most ${formatDuration(roomConfig.shareLinkMaxTtlMs)}`);
+      }
+      ttlMs = requested;
+    }
+
+    const room = await prisma.room.findUnique({ where: { id: String(req.params.id) } });
+    if (!room) {
+      throw new AppError(404, 'Room not found');
+    }
+    const roomRemainingMs = room.expiryDate.getTime() - Date.now();
+    if (roomRemainingMs <= 0) {
+      throw new AppError(403, 'Room has expired');
+    }
+
+    const { link, token } = await shareLinkService.create(room, {
+      ttlMs: Math.min(ttlMs, roomRemainingMs),
+      oneTime,
+    });
+    const basePath = process.env.BASE_PATH || '/api';
+
+    return res.status(201).json({
+      status: 'success',
+      data: {
+        url: `${req.protocol}://${req.get('host')}${basePath}/join/${token}`,
+        token,
+        expiresAt: link.expiresAt,
+        oneTime: link.oneTime,
+      }
+    });
+  } catch (error) {
+    if (error instanceof AppError) {
+      return res.status(error.statusCode).json({ status: 'error', message: error.message });
+    }
+    console.error("Error creating share link:", error);
+    return res.status(500).json({ status: 'error', message: "Failed to create share link" });
+  }
+});
+
 // Validate room access
 router.get("/validate", async (req: Request, res: Response) => {
   try {
@@ -309,6 +1107,12 @@ router.get("/validate", async (req: Request, res: Response) => {
       return res.status(403).json({ error: "Room has expired" });
     }
 
+    // Scheduled rooms can only be joined inside their window
+    const unavailable = unavailableReason(room);
+    if (unavailable) {
+      return res.status(423).json({ error: unavailable });
+    }
+
     // If password is provided, validate it
     if (password && password !== room.password) {
       return res.status(403).json({ error: "Invalid password" });
@@ -331,7 +1135,7 @@ router.get("/validate", async (req: Request, res: Response) => {
 });
 
 // Add a POST endpoint for room validation
-router.post("/validate/:id", roomValidationLimiter, async (req: Request, res: Response, _next: NextFunction) => {
+router.post("/validate/:id", roomValidationLimiter, roomJoinLimiter, async (req: Request, res: Response, _next: NextFunction) => {
   try {
     const { id } = req.params;
     const { password, isPresenter } = req.body;
@@ -378,6 +1182,15 @@ router.post("/validate/:id", roomValidationLimiter, async (req: Request, res: Re
       });
     }
 
+    // Scheduled rooms can only be joined inside their window
+    const unavailable = unavailableReason(room);
+    if (unavailable) {
+      return res.status(423).json({
+        status: 'error',
+        message: unavailable
+      });
+    }
+
     // If password is provided, validate it
     if (password && password !== room.password) {
       return res.status(403).json({ 
@@ -409,15 +1222,40 @@ router.post("/validate/:id", roomValidationLimiter, async (req: Request, res: Re
 router.delete("/:id", async (req: Request, res: Response) => {
   try {
     const { id } = req.params;
-    await prisma.room.delete({
-      where: {
-        id: String(id),
-      },
+
+    // delete returns the removed row, so the response describes exactly what was deleted; a concurrent
+    // delete that got there first leaves nothing to remove (P2025), which is answered like a missing room
+    const room = await prisma.room.delete({ where: { id: String(id) } }).catch((error: unknown) => {
+      if (error instanceof Prisma.PrismaClientKnownRequestError && error.code === 'P2025') {
+        return null;
+      }
+      throw error;
+    });
+
+    if (!room) {
+      // Retried deletes (e.g. after a client timeout) can ask for the room being gone to count as success
+      if (req.query.idempotent === 'true') {
+        return res.status(200).json({
+          status: 'success',
+          data: { deleted: false, room: null }
+        });
+      }
+      return res.status(404).json({ status: 'error', message: 'Room not found' });
+    }
+
+    // Rooms can be joined by either id, so drop both limiter buckets
+    roomJoinLimiter.resetKey(`room:${room.id}`);
+    roomJoinLimiter.resetKey(`room:${room.mirotalkRoomId}`);
+
+    publishRoomEvent({ type: 'room.deleted', roomId: room.id, name: room.name });
+
+    return res.status(200).json({
+      status: 'success',
+      data: { deleted: true, room }
     });
-    return res.status(204).send();
   } catch (error) {
     console.error("Error deleting room:", error);
-    return res.status(500).json({ error: "Failed to delete room" });
+    return res.status(500).json({ status: 'error', message: "Failed to delete room" });
   }
 });
 
@@ -1,18 +1,20 @@
-import express, { Request, Response, NextFunction } from 'express';
+import { Request, Response, NextFunction } from 'express';
 import { authenticateToken } from '../middleware/auth';
 import { blockedIPService } from '../services/blockedIP';
 import { AppError } from '../middleware/errorHandler';
+import { queryInt } from '../utils/queryParams';
 import { body, validationResult } from 'express-validator';
 import { PrismaClient } from '@prisma/client';
+import { asyncRouter } from '../utils/asyncRouter';
 
-const router = express.Router();
+const router = asyncRouter();
 const prisma = new PrismaClient();
 
 // Get all blocked IPs (paginated)
 router.get('/blocked-ips', authenticateToken, async (req: Request, res: Response, next: NextFunction) => {
     try {
-        const page = parseInt(req.query.page as string) || 1;
-        const limit = parseInt(req.query.limit as string) || 10;
+        const page = queryInt(req, 'page', 1, { min: 1 });
+        const limit = queryInt(req, 'limit', 10, { min: 1, max: 100, clamp: true });
         const skip = (page - 1) * limit;
 
         const [blockedIPs, total] = await Promise.all([
@@ -0,0 +1,23 @@
+import { formatDuration } from '../utils/duration';
+import { asyncRouter } from '../utils/asyncRouter';
+
+const router = asyncRouter();
+
+const startedAt = new Date();
+
+/**
+ * @route GET /api/time
+ * @desc Server clock and uptime, so clients can spot clock skew behind "token instantly expired" reports
+ * @access Public
+ */
+router.get('/', (_req, res) => {
+  const now = new Date();
+  res.set('Cache-Control', 'no-store');
+  return res.status(200).json({
+    now: now.toISOString(),
+    uptime: formatDuration(now.getTime() - startedAt.getTime()),
+    startedAt: startedAt.toISOString()
+  });
+});
+
+export default router;
@@ -1,4 +1,4 @@
-import express, { Request, Response } from 'express';
+import { Request, Response } from 'express';
 import { PrismaClient } from '@prisma/client';
 import multer from 'multer';
 import path from 'path';
@@ -12,8 +12,10 @@ import { logger } from '../utils/logger';
 import { getTelegramBot } from '../services/telegram/telegramBot';
 // Import the controller function for the finished upload hook
 import { handleProcessFinishedUpload } from '../controllers/uploadController';
+import { BoundedCache } from '../utils/boundedCache';
+import { asyncRouter } from '../utils/asyncRouter';
 
-const router = express.Router();
+const router = asyncRouter();
 const prisma = new PrismaClient();
 
 // Removed the separate /process-finished route, as all hooks go to /hook-progress now.
@@ -27,7 +29,8 @@ interface TusdUploadInfo {
   size?: number;
   filename?: string;
 }
-const tusdProgressCache = new Map<string, TusdUploadInfo>();
+// Bounded so uploads that never finish or terminate don't accumulate forever
+const tusdProgressCache = new BoundedCache<string, TusdUploadInfo>();
 const TUSD_DATA_DIR = process.env.TUSD_DATA_DIR || '/srv/tusd-data'; // Get tusd data dir
 // --- End In-memory storage ---
 
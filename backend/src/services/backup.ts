@@ -0,0 +1,89 @@
+import { execFile } from 'child_process';
+import fs from 'fs/promises';
+import path from 'path';
+import { promisify } from 'util';
+import { backupConfig } from '../config/backupConfig';
+import { logger } from '../utils/logger';
+
+const execFileAsync = promisify(execFile);
+
+export interface BackupResult {
+  path: string;
+  size: number;
+  createdAt: string;
+}
+
+// pg_dump rejects Prisma-specific query parameters such as ?schema=public
+const getPgDumpConnectionString = (): string => {
+  const databaseUrl = process.env.DATABASE_URL;
+  if (!databaseUrl) {
+    throw new Error('DATABASE_URL environment variable is not set');
+  }
+
+  const url = new URL(databaseUrl);
+  url.searchParams.delete('schema');
+  url.searchParams.delete('connection_limit');
+  url.searchParams.delete('pool_timeout');
+  return url.toString();
+};
+
+/**
+ * Write a consistent snapshot of the database to the given file.
+ * pg_dump runs inside a single transaction, so the dump is consistent
+ * even while the application keeps writing.
+ */
+export const dumpDatabase = async (filePath: string): Promise<BackupResult> => {
+  await fs.mkdir(path.dirname(filePath), { recursive: true });
+
+  await execFileAsync('pg_dump', [
+    '--format=custom',
+    '--no-owner',
+    `--file=${filePath}`,
+    getPgDumpConnectionString(),
+  ]);
+
+  const stats = await fs.stat(filePath);
+  return {
+    path: filePath,
+    size: stats.size,
+    createdAt: stats.mtime.toISOString(),
+  };
+};
+
+/**
+ * Create a timestamped backup in the configured backup directory
+ */
+export const createBackup = async (): Promise<BackupResult> => {
+  const timestamp = new Date().toISOString().replace(/[:.]/g, '-');
+  const filePath = path.join(backupConfig.directory, `colourstream-${timestamp}.dump`);
+
+  logger.info('Starting database backup', { path: filePath });
+  const result = await dumpDatabase(filePath);
+  logger.info('Database backup completed', { path: result.path, size: result.size });
+
+  return result;
+};
+
+let backupInterval: NodeJS.Timeout | null = null;
+
+export const startBackupScheduler = (): void => {
+  if (backupInterval || backupConfig.intervalMinutes <= 0) {
+    return;
+  }
+
+  backupInterval = setInterval(() => {
+    createBackup().catch(error => logger.error('Scheduled database backup failed:', error));
+  }, backupConfig.intervalMinutes * 60 * 1000);
+
+  logger.info(`Scheduled database backups every ${backupConfig.intervalMinutes} minutes`, {
+    directory: backupConfig.directory,
+  });
+};
+
+export const stopBackupScheduler = (): void => {
+  if (backupInterval) {
+    clearInterval(backupInterval);
+    backupInterval = null;
+    logger.info('Database backup scheduler stopped');
+  }
+};
@@ -2,6 +2,8 @@ import { auth, ConfigParams } from 'express-openid-connect';
 import { PrismaClient } from '@prisma/client';
 import { logger } from '../utils/logger';
 import { Express } from 'express';
+import { buildSessionCookie } from '../config/cookieConfig';
+import { requireSecret, secrets } from '../config/secrets';
 
 const prisma = new PrismaClient();
 
@@ -10,11 +12,11 @@ let oidcConfig: ConfigParams = {
   authRequired: false,
   auth0Logout: false,
   idpLogout: true,
-  secret: process.env.ADMIN_AUTH_SECRET || 'a-long-random-string',
+  secret: requireSecret('ADMIN_AUTH_SECRET'),
   baseURL: process.env.BASE_URL || 'http://localhost:3000',
   clientID: process.env.OIDC_CLIENT_ID || '',
   issuerBaseURL: process.env.OIDC_ISSUER_BASE_URL || '',
-  clientSecret: process.env.OIDC_CLIENT_SECRET || '',
+  clientSecret: secrets.get('OIDC_CLIENT_SECRET') || '',
   routes: {
     login: '/api/auth/oidc/login',
     callback: '/api/auth/oidc/callback',
@@ -32,10 +34,11 @@ let oidcConfig: ConfigParams = {
 async function initializeOIDCFromEnv(): Promise<boolean> {
   try {
     // Check if environment variables are set
+    const clientSecret = secrets.get('OIDC_CLIENT_SECRET');
     if (
       process.env.OIDC_ISSUER_BASE_URL &&
       process.env.OIDC_CLIENT_ID &&
-      process.env.OIDC_CLIENT_SECRET
+      clientSecret
     ) {
       logger.info('Initializing OIDC from environment variables');
       
@@ -43,7 +46,7 @@ async function initializeOIDCFromEnv(): Promise<boolean> {
         ...oidcConfig,
         issuerBaseURL: process.env.OIDC_ISSUER_BASE_URL,
         clientID: process.env.OIDC_CLIENT_ID,
-        clientSecret: process.env.OIDC_CLIENT_SECRET,
+        clientSecret,
         baseURL: process.env.BASE_URL || oidcConfig.baseURL,
       };
       
@@ -52,7 +55,7 @@ async function initializeOIDCFromEnv(): Promise<boolean> {
         enabled: true,
         providerName: 'Environment',
         clientId: process.env.OIDC_CLIENT_ID,
-        clientSecret: process.env.OIDC_CLIENT_SECRET,
+        clientSecret,
         discoveryUrl: process.env.OIDC_ISSUER_BASE_URL,
         scope: process.env.OIDC_SCOPE || 'openid profile email'
       });
@@ -198,8 +201,14 @@ export function initializeOIDCMiddleware(app: Express): void {
     return;
   }
   
-  // Initialize OIDC middleware with current config
-  app.use(auth(oidcConfig));
+  // Initialize OIDC middleware with current config and hardened session cookie attributes
+  app.use(auth({
+    ...oidcConfig,
+    session: {
+      ...oidcConfig.session,
+      cookie: buildSessionCookie(oidcConfig.baseURL || ''),
+    },
+  }));
   
   logger.info('OIDC middleware initialized with current configuration');
 }
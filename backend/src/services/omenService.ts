@@ -1,5 +1,6 @@
 import axios, { AxiosError } from 'axios';
 import { logger } from '../utils/logger';
+import { requireSecret } from '../config/secrets';
 
 interface OvenStatistics {
     connections: {
@@ -34,18 +35,13 @@ class OvenMediaEngineService {// temporary removeal of export on 20250627
 
     constructor() {
         this.baseURL = process.env.OME_API_URL || 'http://origin:8081';
-        this.accessToken = process.env.OME_API_ACCESS_TOKEN || '0fc62ea62790ad7c';
+        this.accessToken = requireSecret('OME_API_ACCESS_TOKEN');
         
         logger.info(`Initialized OvenMediaEngine Service with URL: ${this.baseURL}`);
-        logger.info(`Using API access token: ${this.accessToken ? '********' : 'default token'}`);
         
         if (!this.baseURL) {
             logger.error('OvenMediaEngine API URL is not configured! Set OME_API_URL environment variable.');
         }
-        
-        if (!this.accessToken) {
-            logger.error('OvenMediaEngine API access token is not configured! Set OME_API_ACCESS_TOKEN environment variable.');
-        }
     }
 
     private validateParameters(...params: string[]) {
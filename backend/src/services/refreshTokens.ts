@@ -0,0 +1,119 @@
+import { createHash, randomBytes, randomUUID } from 'crypto';
+import { Prisma } from '@prisma/client';
+import prisma from '../lib/prisma';
+import { authConfig } from '../config/authConfig';
+import { AppError } from '../middleware/errorHandler';
+import { logger } from '../utils/logger';
+import { accessTokenCutoff, revokeSession } from './sessionRevocations';
+
+interface IssuedRefreshToken {
+  refreshToken: string;
+  familyId: string;
+  expiresAt: Date;
+}
+
+interface RotatedRefreshToken extends IssuedRefreshToken {
+  userId: string;
+}
+
+// Only the hash is stored, so a database leak does not expose usable tokens
+const hashToken = (token: string): string => {
+  return createHash('sha256').update(token).digest('hex');
+};
+
+// Revoke the chain and the access tokens already issued from it; the token family is the session (sid)
+const revokeFamily = async (familyId: string): Promise<void> => {
+  await prisma.refreshToken.updateMany({
+    where: { familyId, revokedAt: null },
+    data: { revokedAt: new Date() },
+  });
+  revokeSession(familyId, accessTokenCutoff());
+};
+
+// The primary client or a transaction, so rotation can claim and replace a token in one step
+const createToken = async (
+  client: Pick<Prisma.TransactionClient, 'refreshToken'>,
+  userId: string,
+  familyId: string,
+): Promise<IssuedRefreshToken & { id: string }> => {
+  const refreshToken = randomBytes(48).toString('base64url');
+  const expiresAt = new Date(Date.now() + authConfig.refreshTtlSeconds * 1000);
+
+  const record = await client.refreshToken.create({
+    data: {
+      tokenHash: hashToken(refreshToken),
+      familyId,
+      userId,
+      expiresAt,
+    },
+  });
+
+  return { id: record.id, refreshToken, familyId, expiresAt };
+};
+
+export const refreshTokenService = {
+  // Issue a refresh token, starting a new rotation chain unless a familyId is given
+  async issue(userId: string, familyId: string = randomUUID()): Promise<IssuedRefreshToken & { id: string }> {
+    return createToken(prisma, userId, familyId);
+  },
+
+  // Exchange a refresh token for a new one, invalidating the old token
+  async rotate(refreshToken: string): Promise<RotatedRefreshToken> {
+    const existing = await prisma.refreshToken.findUnique({
+      where: { tokenHash: hashToken(refreshToken) },
+    });
+
+    if (!existing) {
+      throw new AppError(401, 'Invalid refresh token');
+    }
+
+    if (existing.revokedAt) {
+      // A rotated token being presented again means it was copied; revoke the whole chain
+      logger.warn('Refresh token reuse detected, revoking token family', {
+        familyId: existing.familyId,
+        userId: existing.userId,
+      });
+      await revokeFamily(existing.familyId);
+      throw new AppError(401, 'Refresh token has been revoked');
+    }
+
+    if (existing.expiresAt <= new Date()) {
+      throw new AppError(401, 'Refresh token has expired');
+    }
+
+    // Claim the token atomically so two concurrent refreshes cannot both succeed. Its replacement is
+    // created in the same transaction: other replicas treat a family with no live token as revoked.
+    const next = await prisma.$transaction(async (tx) => {
+      const claimed = await tx.refreshToken.updateMany({
+        where: { id: existing.id, revokedAt: null },
+        data: { revokedAt: new Date() },
+      });
+      if (claimed.count === 0) {
+        return null;
+      }
+
+      const issued = await createToken(tx, existing.userId, existing.familyId);
+      await tx.refreshToken.update({
+        where: { id: existing.id },
+        data: { replacedById: issued.id },
+      });
+      return issued;
+    });
+
+    if (!next) {
+      logger.warn('Concurrent refresh token reuse detected, revoking token family', {
+        familyId: existing.familyId,
+        userId: existing.userId,
+      });
+      await revokeFamily(existing.familyId);
+      throw new AppError(401, 'Refresh token has been revoked');
+    }
+
+    return {
+      userId: existing.userId,
+      refreshToken: next.refreshToken,
+      familyId: next.familyId,
+      expiresAt: next.expiresAt,
+    };
+  },
+};
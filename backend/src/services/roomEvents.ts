@@ -0,0 +1,28 @@
+import { EventEmitter } from 'events';
+
+type RoomEventType = 'room.created' | 'room.deleted' | 'room.renamed';
+
+export interface RoomEvent {
+  type: RoomEventType;
+  roomId: string;
+  name: string;
+  previousName?: string;
+  at: string;
+}
+
+// In-process bus for room lifecycle events; the rooms routes publish, push channels subscribe
+const bus = new EventEmitter();
+// Every connected socket adds a listener, so don't warn at the default of 10
+bus.setMaxListeners(0);
+
+export const publishRoomEvent = (event: Omit<RoomEvent, 'at'>): void => {
+  bus.emit('room', { ...event, at: new Date().toISOString() });
+};
+
+// Returns an unsubscribe function
+export const subscribeRoomEvents = (listener: (event: RoomEvent) => void): (() => void) => {
+  bus.on('room', listener);
+  return () => {
+    bus.off('room', listener);
+  };
+};
@@ -0,0 +1,76 @@
+import { Prisma } from '@prisma/client';
+import { prismaRead } from '../lib/prisma';
+import { roomConfig } from '../config/roomConfig';
+import { logger } from '../utils/logger';
+
+// Build a prefix tsquery ("stu:* & a:*") from the words in q, or null if it has none
+const toPrefixQuery = (q: string): string | null => {
+  const words = q.toLowerCase().match(/[\p{L}\p{N}]+/gu);
+  return words ? words.map(word => `${word}:*`).join(' & ') : null;
+};
+
+interface FullTextFilters {
+  createdAfter?: Date;
+  createdBefore?: Date;
+  ids?: string[];
+}
+
+interface FullTextPage {
+  // Ids of the rooms on the requested page, in page order
+  ids: string[];
+  // Rooms matching q and the filters, ignoring the cursor
+  total: number;
+}
+
+/**
+ * Match q against room names with Postgres full-text search, applying the list filters and
+ * keyset page in the same query so only one page of ids comes back. Returns null when
+ * ROOM_SEARCH_FTS is off, q has no words or the query fails, so callers use roomNameFilter instead.
+ */
+export const fullTextRoomPage = async (
+  q: string,
+  filters: FullTextFilters,
+  page?: { sinceId?: string; limit: number },
+): Promise<FullTextPage | null> => {
+  const tsquery = roomConfig.fullTextSearch ? toPrefixQuery(q) : null;
+  if (!tsquery) {
+    return null;
+  }
+
+  // Served by the Room_name_fts_idx GIN index created in docker-entrypoint.sh
+  const conditions = [Prisma.sql`to_tsvector('simple', "name") @@ to_tsquery('simple', ${tsquery})`];
+  if (filters.createdAfter) {
+    conditions.push(Prisma.sql`"createdAt" >= ${filters.createdAfter}`);
+  }
+  if (filters.createdBefore) {
+    conditions.push(Prisma.sql`"createdAt" <= ${filters.createdBefore}`);
+  }
+  if (filters.ids) {
+    conditions.push(Prisma.sql`"id" IN (${Prisma.join(filters.ids)})`);
+  }
+  const matching = Prisma.join(conditions, ' AND ');
+
+  // Same order as the list route: by id when paging, newest first otherwise
+  const pageQuery = page
+    ? Prisma.sql`
+      SELECT "id" FROM "Room"
+      WHERE ${matching}${page.sinceId ? Prisma.sql` AND "id" > ${page.sinceId}` : Prisma.empty}
+      ORDER BY "id" ASC
+      LIMIT ${page.limit}
+    `
+    : Prisma.sql`SELECT "id" FROM "Room" WHERE ${matching} ORDER BY "createdAt" DESC`;
+
+  try {
+    const [rows, [{ total }]] = await Promise.all([
+      prismaRead.$queryRaw<{ id: string }[]>(pageQuery),
+      prismaRead.$queryRaw<{ total: number }[]>`SELECT COUNT(*)::int AS "total" FROM "Room" WHERE ${matching}`,
+    ]);
+    return { ids: rows.map(row => row.id), total };
+  } catch (error) {
+    logger.warn('Full-text room search failed, falling back to substring match', { error });
+    return null;
+  }
+};
+
+// Case-insensitive substring match on the room name
+export const roomNameFilter = (q: string): Prisma.RoomWhereInput => ({ name: { contains: q, mode: 'insensitive' } });
@@ -0,0 +1,24 @@
+import { Prisma } from '@prisma/client';
+import prisma from '../lib/prisma';
+
+// The primary client or a transaction; inside a transaction, a rollback also gives the value back
+export type SequenceClient = Pick<Prisma.TransactionClient, '$queryRaw'>;
+
+interface SequenceRow {
+  value: number;
+}
+
+/**
+ * Atomically increment and return the named counter, starting at 1.
+ * The upsert runs as a single statement, so concurrent callers (in this
+ * process or another replica) always receive distinct values.
+ */
+export const nextSequenceValue = async (name: string, client: SequenceClient = prisma): Promise<number> => {
+  const rows = await client.$queryRaw<SequenceRow[]>`
+    INSERT INTO "Sequence" ("name", "value")
+    VALUES (${name}, 1)
+    ON CONFLICT ("name") DO UPDATE SET "value" = "Sequence"."value" + 1
+    RETURNING "value"
+  `;
+  return rows[0].value;
+};
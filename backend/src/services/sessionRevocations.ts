@@ -0,0 +1,60 @@
+import prisma from '../lib/prisma';
+import { authConfig } from '../config/authConfig';
+import { BoundedCache } from '../utils/boundedCache';
+import { logger } from '../utils/logger';
+
+// Session ids (the `sid` claim) revoked by this process, whose access tokens must be rejected before
+// they expire. Entries are dropped once every access token issued for the session would have expired anyway.
+const revokedSessions = new Map<string, number>();
+
+// What the database said about sessions revoked elsewhere (another replica), for SESSION_REVOCATION_CACHE_TTL
+const databaseAnswers = new BoundedCache<string, boolean>({ ttlMs: authConfig.revocationCacheTtlMs });
+
+const pruneExpired = (now: number): void => {
+  for (const [sid, until] of revokedSessions) {
+    if (until <= now) {
+      revokedSessions.delete(sid);
+    }
+  }
+};
+
+// Access tokens outlive their session's revocation by at most one token lifetime
+export const accessTokenCutoff = (): Date => new Date(Date.now() + authConfig.tokenTtlSeconds * 1000);
+
+export const revokeSession = (sid: string, until: Date): void => {
+  pruneExpired(Date.now());
+  revokedSessions.set(sid, until.getTime());
+};
+
+// A session is a refresh token family, and rotation always leaves its newest token unrevoked,
+// so a family without one has been revoked, by whichever replica did it
+const isRevokedInDatabase = async (sid: string): Promise<boolean> => {
+  const cached = databaseAnswers.get(sid);
+  if (cached !== undefined) {
+    return cached;
+  }
+
+  try {
+    const live = await prisma.refreshToken.findFirst({
+      where: { familyId: sid, revokedAt: null },
+      select: { id: true },
+    });
+    databaseAnswers.set(sid, !live);
+    return !live;
+  } catch (error) {
+    // Signatures are still checked; without the database only this process's revocations apply
+    logger.warn('Could not check session revocation in the database', { sid, error });
+    return false;
+  }
+};
+
+export const isSessionRevoked = async (sid: string | undefined): Promise<boolean> => {
+  if (!sid) {
+    return false;
+  }
+  const until = revokedSessions.get(sid);
+  if (until !== undefined && until > Date.now()) {
+    return true;
+  }
+  return isRevokedInDatabase(sid);
+};
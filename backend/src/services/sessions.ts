@@ -0,0 +1,90 @@
+import prisma from '../lib/prisma';
+import { authConfig } from '../config/authConfig';
+import { AppError } from '../middleware/errorHandler';
+import { logger } from '../utils/logger';
+import { accessTokenCutoff, revokeSession } from './sessionRevocations';
+
+export interface AdminSession {
+  jti: string;
+  username: string;
+  issuedAt: Date;
+  expiresAt: Date;
+  lastRefreshedAt: Date;
+}
+
+export const sessionService = {
+  // A session is a refresh token family; it is active while it still holds a usable token
+  async list(): Promise<AdminSession[]> {
+    const now = new Date();
+    const live = await prisma.refreshToken.findMany({
+      where: { revokedAt: null, expiresAt: { gt: now } },
+      select: { familyId: true, userId: true, expiresAt: true, createdAt: true },
+    });
+    if (live.length === 0) {
+      return [];
+    }
+
+    const history = await prisma.refreshToken.groupBy({
+      by: ['familyId'],
+      where: { familyId: { in: live.map(token => token.familyId) } },
+      _min: { createdAt: true },
+    });
+    const issuedAtByFamily = new Map(history.map(entry => [entry.familyId, entry._min.createdAt]));
+
+    return live
+      .map(token => ({
+        jti: token.familyId,
+        username: token.userId,
+        // The family includes this token, so it was issued no later than this token was
+        issuedAt: issuedAtByFamily.get(token.familyId) ?? token.createdAt,
+        expiresAt: token.expiresAt,
+        // Each refresh rotates the token and leaves only the newest one live, so this is the last refresh
+        // (or the login, if the session never refreshed); access tokens used in between are not tracked
+        lastRefreshedAt: token.createdAt,
+      }))
+      .sort((a, b) => b.lastRefreshedAt.getTime() - a.lastRefreshedAt.getTime());
+  },
+
+  // Revoke every refresh token in the session and reject its outstanding access tokens
+  async revoke(jti: string): Promise<void> {
+    const result = await prisma.refreshToken.updateMany({
+      where: { familyId: jti, revokedAt: null, expiresAt: { gt: new Date() } },
+      data: { revokedAt: new Date() },
+    });
+
+    if (result.count === 0) {
+      throw new AppError(404, 'Session not found');
+    }
+
+    revokeSession(jti, accessTokenCutoff());
+    logger.info('Admin session revoked', { jti });
+  },
+
+  // Restore revocations after a restart so access tokens of revoked sessions stay rejected
+  async loadRevoked(): Promise<void> {
+    const since = new Date(Date.now() - authConfig.tokenTtlSeconds * 1000);
+    const recent = await prisma.refreshToken.groupBy({
+      by: ['familyId'],
+      where: { revokedAt: { gt: since } },
+    });
+    if (recent.length === 0) {
+      return;
+    }
+
+    // Rotation always leaves the newest token live, so a family with none left was revoked
+    const stillLive = await prisma.refreshToken.findMany({
+      where: {
+        familyId: { in: recent.map(entry => entry.familyId) },
+        revokedAt: null,
+        expiresAt: { gt: new Date() },
+      },
+      select: { familyId: true },
+    });
+    const liveFamilies = new Set(stillLive.map(token => token.familyId));
+
+    const cutoff = accessTokenCutoff();
+    recent
+      .filter(entry => !liveFamilies.has(entry.familyId))
+      .forEach(entry => revokeSession(entry.familyId, cutoff));
+  },
+};
@@ -0,0 +1,138 @@
+import { roomConfig } from '../config/roomConfig';
+import { AppError } from '../middleware/errorHandler';
+import { logger } from '../utils/logger';
+
+// The values each settings key may take, e.g. { codec: ['h264', 'vp8'] }; keys not listed are not checked
+type Capabilities = Record<string, string[]>;
+
+interface UnsupportedSetting {
+  key: string;
+  value: string;
+}
+
+/**
+ * Checks room settings against what the media server supports.
+ * Routes go through this interface, so where the capabilities come from
+ * (a static allowlist or the media server itself) can change without touching them.
+ */
+interface SettingsValidator {
+  findUnsupported(settings: Record<string, unknown>): Promise<UnsupportedSetting[]>;
+}
+
+const CAPABILITIES_TIMEOUT_MS = 5000;
+
+// A setting may hold one value or a list of them; objects and nulls are left alone
+const settingValues = (value: unknown): string[] => {
+  const values = Array.isArray(value) ? value : [value];
+  return values
+    .filter(item => typeof item === 'string' || typeof item === 'number' || typeof item === 'boolean')
+    .map(item => String(item));
+};
+
+const findUnsupported = (capabilities: Capabilities, settings: Record<string, unknown>): UnsupportedSetting[] => {
+  const unsupported: UnsupportedSetting[] = [];
+  for (const [key, value] of Object.entries(settings)) {
+    const supported = Object.prototype.hasOwnProperty.call(capabilities, key) ? capabilities[key] : undefined;
+    if (!supported) {
+      continue;
+    }
+    const allowed = new Set(supported.map(option => option.toLowerCase()));
+    settingValues(value)
+      .filter(item => !allowed.has(item.toLowerCase()))
+      .forEach(item => unsupported.push({ key, value: item }));
+  }
+  return unsupported;
+};
+
+// Checks settings against ROOM_SETTINGS_ALLOWLIST; an empty allowlist accepts everything
+class AllowlistSettingsValidator implements SettingsValidator {
+  constructor(private readonly capabilities: Capabilities) {}
+
+  async findUnsupported(settings: Record<string, unknown>): Promise<UnsupportedSetting[]> {
+    return findUnsupported(this.capabilities, settings);
+  }
+}
+
+// The endpoint answers with an object of string lists, e.g. { "codec": ["h264", "vp8"] }; anything else is ignored
+const parseCapabilities = (body: unknown): Capabilities => {
+  if (typeof body !== 'object' || body === null || Array.isArray(body)) {
+    throw new Error('Capabilities must be a JSON object');
+  }
+  const capabilities: Capabilities = {};
+  for (const [key, values] of Object.entries(body)) {
+    if (Array.isArray(values)) {
+      capabilities[key] = values.filter((value): value is string => typeof value === 'string');
+    }
+  }
+  return capabilities;
+};
+
+// Checks settings against the capabilities the media server advertises at ROOM_SETTINGS_CAPABILITIES_URL,
+// fetched at most once per ROOM_SETTINGS_CAPABILITIES_TTL
+class CapabilitiesEndpointValidator implements SettingsValidator {
+  private capabilities: Capabilities = {};
+  private fetchedAt = 0;
+  private pending?: Promise<Capabilities>;
+
+  constructor(private readonly url: string, private readonly ttlMs: number) {}
+
+  async findUnsupported(settings: Record<string, unknown>): Promise<UnsupportedSetting[]> {
+    return findUnsupported(await this.current(), settings);
+  }
+
+  private current(): Promise<Capabilities> {
+    if (Date.now() - this.fetchedAt < this.ttlMs) {
+      return Promise.resolve(this.capabilities);
+    }
+    // Concurrent requests share one lookup
+    if (!this.pending) {
+      this.pending = this.refresh().finally(() => {
+        this.pending = undefined;
+      });
+    }
+    return this.pending;
+  }
+
+  private async refresh(): Promise<Capabilities> {
+    try {
+      const response = await fetch(this.url, { signal: AbortSignal.timeout(CAPABILITIES_TIMEOUT_MS) });
+      if (!response.ok) {
+        throw new Error(`HTTP ${response.status}`);
+      }
+      this.capabilities = parseCapabilities(await response.json());
+    } catch (error) {
+      // Validation is advisory: keep the last known capabilities (none at first) rather than block room edits
+      logger.warn('Could not fetch media server capabilities, keeping the previous ones', {
+        url: this.url,
+        error: error instanceof Error ? error.message : String(error),
+      });
+    }
+    // A failed lookup is not retried until the cache expires, so an unreachable server costs one timeout per TTL
+    this.fetchedAt = Date.now();
+    return this.capabilities;
+  }
+}
+
+const createSettingsValidator = (): SettingsValidator => {
+  if (roomConfig.settingsCapabilitiesUrl) {
+    return new CapabilitiesEndpointValidator(roomConfig.settingsCapabilitiesUrl, roomConfig.settingsCapabilitiesTtlMs);
+  }
+  return new AllowlistSettingsValidator(roomConfig.settingsAllowlist);
+};
+
+const settingsValidator: SettingsValidator = createSettingsValidator();
+
+/**
+ * Reject settings values the media server does not support with a 422 listing them,
+ * e.g. "Unsupported settings: codec=av1". Only the values given are checked, so a room
+ * whose stored settings predate a capability change can still be edited.
+ */
+export const assertSettingsSupported = async (
+  settings: Record<string, unknown>,
+  validator: SettingsValidator = settingsValidator,
+): Promise<void> => {
+  const unsupported = await validator.findUnsupported(settings);
+  if (unsupported.length > 0) {
+    throw new AppError(422, `Unsupported settings: ${unsupported.map(({ key, value }) => `${key}=${value}`).join(', ')}`);
+  }
+};
@@ -0,0 +1,79 @@
+import crypto from 'crypto';
+import jwt, { JwtPayload } from 'jsonwebtoken';
+import { Room } from '@prisma/client';
+import prisma from '../lib/prisma';
+import { secrets } from '../config/secrets';
+import { AppError } from '../middleware/errorHandler';
+import { logger } from '../utils/logger';
+
+const SHARE_LINK_PURPOSE = 'room-share';
+
+// Signed with a key derived from ADMIN_AUTH_SECRET, so a share token can never pass as an admin token
+const signingKey = (): Buffer => {
+  const secret = secrets.get('ADMIN_AUTH_SECRET');
+  if (!secret) {
+    throw new AppError(500, 'ADMIN_AUTH_SECRET is not set');
+  }
+  return crypto.createHmac('sha256', secret).update(SHARE_LINK_PURPOSE).digest();
+};
+
+interface ShareLinkOptions {
+  ttlMs: number;
+  oneTime: boolean;
+}
+
+export const shareLinkService = {
+  // Record the link and sign a token naming it; the token expires with the link
+  async create(room: Pick<Room, 'id'>, { ttlMs, oneTime }: ShareLinkOptions) {
+    const expiresAt = new Date(Date.now() + ttlMs);
+    const link = await prisma.roomShareLink.create({
+      data: { roomId: room.id, oneTime, expiresAt },
+    });
+
+    const token = jwt.sign({ purpose: SHARE_LINK_PURPOSE }, signingKey(), {
+      jwtid: link.id,
+      subject: room.id,
+      expiresIn: Math.ceil(ttlMs / 1000),
+    });
+
+    logger.info('Created room share link', { roomId: room.id, linkId: link.id, oneTime, expiresAt });
+    return { link, token };
+  },
+
+  // Resolve a token to its live link and room, rejecting forged, expired and used links
+  async resolve(token: string) {
+    const key = signingKey();
+    let payload: JwtPayload;
+    try {
+      payload = jwt.verify(token, key) as JwtPayload;
+    } catch (error) {
+      throw new AppError(401, 'Invalid or expired share link');
+    }
+    if (payload.purpose !== SHARE_LINK_PURPOSE || !payload.jti) {
+      throw new AppError(401, 'Invalid or expired share link');
+    }
+
+    const link = await prisma.roomShareLink.findUnique({
+      where: { id: payload.jti },
+      include: { room: true },
+    });
+    if (!link) {
+      throw new AppError(404, 'Room not found');
+    }
+    if (link.consumedAt) {
+      throw new AppError(410, 'Share link has already been used');
+    }
+    return link;
+  },
+
+  // Mark a one-time link used; only the first of several concurrent redemptions wins
+  async consume(linkId: string): Promise<void> {
+    const { count } = await prisma.roomShareLink.updateMany({
+      where: { id: linkId, consumedAt: null },
+      data: { consumedAt: new Date() },
+    });
+    if (count === 0) {
+      throw new AppError(410, 'Share link has already been used');
+    }
+  },
+};
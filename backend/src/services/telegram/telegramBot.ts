@@ -1,6 +1,7 @@
 import axios from 'axios';
 import { logger } from '../../utils/logger';
 import { PrismaClient } from '@prisma/client';
+import { BoundedCache } from '../../utils/boundedCache';
 
 // Initialize Prisma client for database operations
 const prisma = new PrismaClient();
@@ -15,15 +16,15 @@ export class TelegramBot {
   private botToken: string;
   private chatId: string;
   private baseUrl: string;
-  private messageIdCache: Map<string, number>; // In-memory cache for message IDs
+  private messageIdCache: BoundedCache<string, number>; // In-memory cache for message IDs, backed by the database
   private uploadInfoCache: Map<string, any> = new Map<string, any>(); // Cache for upload information
-  private lastReportedProgress: Map<string, number> = new Map<string, number>(); // Cache for last reported progress percentage
+  private lastReportedProgress = new BoundedCache<string, number>(); // Cache for last reported progress percentage
 
   constructor(config: TelegramConfig) {
     this.botToken = config.botToken;
     this.chatId = config.chatId;
     this.baseUrl = `https://api.telegram.org/bot${this.botToken}`;
-    this.messageIdCache = new Map<string, number>(); // Initialize the cache
+    this.messageIdCache = new BoundedCache<string, number>(); // Initialize the cache
     
     // Log initialization
     console.log('[TELEGRAM-DEBUG] Telegram API Base URL:', `https://api.telegram.org/bot${this.botToken.substring(0, 10)}...`);
@@ -2,6 +2,9 @@ import WebSocket from 'ws';
 import { Server } from 'http';
 import { verifyToken } from '../middleware/auth';
 import { logger } from '../utils/logger';
+import { subscribeRoomEvents } from './roomEvents';
+import { serverConfig } from '../config/serverConfig';
+import { isAllowedOrigin } from '../utils/allowedOrigin';
 
 interface WebSocketClient extends WebSocket {
   isAlive: boolean;
@@ -17,6 +20,15 @@ interface OBSStatusMessage {
   error?: string;
 }
 
+// Connection counts for the health endpoint; the server instance lives in index.ts
+const subscriberStats = {
+  connected: 0,
+  max: serverConfig.maxWebSocketClients,
+  rejected: 0,
+};
+
+export const getWebSocketStats = () => ({ ...subscriberStats });
+
 class WebSocketService {
   private wss: WebSocket.Server;
   private clients: Set<WebSocketClient> = new Set();
@@ -29,6 +41,29 @@ class WebSocketService {
       // path: '/api/ws',
       // Increase timeout values to prevent premature disconnections
       clientTracking: true,
+      // Refuse the upgrade with a 403 for browser origins outside the CORS allowlist, so another
+      // site can't subscribe to room events through a visitor's browser, and with a 503 once
+      // WS_MAX_CLIENTS sockets are open, so a flood of subscribers can't exhaust the server
+      verifyClient: (info, callback) => {
+        if (!isAllowedOrigin(info.origin)) {
+          logger.warn('WebSocket connection rejected: origin not allowed', {
+            origin: info.origin,
+            remoteAddress: info.req.socket.remoteAddress
+          });
+          callback(false, 403, 'Origin not allowed');
+          return;
+        }
+        if (this.wss.clients.size >= serverConfig.maxWebSocketClients) {
+          subscriberStats.rejected++;
+          logger.warn('WebSocket connection rejected: too many clients', {
+            connected: this.wss.clients.size,
+            max: serverConfig.maxWebSocketClients
+          });
+          callback(false, 503, 'Too many WebSocket clients');
+          return;
+        }
+        callback(true);
+      },
     });
     
     logger.info('WebSocket server initialized without path restriction to handle all WebSocket connections');
@@ -38,9 +73,14 @@ class WebSocketService {
 
   private setupWebSocketServer() {
     this.wss.on('connection', async (ws: WebSocketClient, request) => {
-      // Extract token from query parameters
+      subscriberStats.connected = this.wss.clients.size;
+      ws.once('close', () => {
+        subscriberStats.connected = this.wss.clients.size;
+      });
+
+      // Extract token from query parameters, or the Authorization header for non-browser clients
       const url = new URL(request.url || '', `http://${request.headers.host || 'localhost'}`);
-      const token = url.searchParams.get('token');
+      const token = url.searchParams.get('token') || request.headers.authorization?.split(' ')[1];
       const path = url.pathname;
       
       logger.info(`WebSocket connection attempt to path: ${path}`, {
@@ -107,6 +147,16 @@ class WebSocketService {
           logger.info(`Added client ${ws.id} to WebSocketService. Total clients: ${websocketService.getClientCount()}`);
         }
 
+        // Push room lifecycle events (created/deleted/renamed) as JSON frames
+        let unsubscribeRoomEvents: (() => void) | undefined;
+        if (path.endsWith('/rooms/ws')) {
+          unsubscribeRoomEvents = subscribeRoomEvents((event) => {
+            if (ws.readyState === WebSocket.OPEN) {
+              ws.send(JSON.stringify(event));
+            }
+          });
+        }
+
         ws.on('pong', () => {
           ws.isAlive = true;
         });
@@ -120,6 +170,7 @@ class WebSocketService {
 
         ws.on('close', (code, reason) => {
           this.clients.delete(ws);
+          unsubscribeRoomEvents?.();
           
           // Also remove from WebSocketService if it was added
           if (ws.id && path.includes('/api/ws/obs-status')) {
@@ -142,6 +193,7 @@ class WebSocketService {
             stack: error.stack
           });
           this.clients.delete(ws);
+          unsubscribeRoomEvents?.();
         });
 
       } catch (error) {
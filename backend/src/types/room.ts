@@ -10,4 +10,6 @@ export type RoomCreateInput = {
   link: string;
   presenterLink?: string;
   mirotalkToken?: string;
+  availableFrom?: Date;
+  availableUntil?: Date;
 };
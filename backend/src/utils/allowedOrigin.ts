@@ -0,0 +1,13 @@
+import { corsConfig } from '../config/corsConfig';
+
+/**
+ * Whether a request's Origin header is on the CORS allowlist.
+ * Requests without an Origin header come from non-browser clients (curl, the OBS bridge)
+ * and are allowed; the opaque "null" origin is not.
+ */
+export const isAllowedOrigin = (origin: string | undefined, allowedOrigins: string[] = corsConfig.allowedOrigins): boolean => {
+  if (!origin) {
+    return true;
+  }
+  return allowedOrigins.includes(origin);
+};
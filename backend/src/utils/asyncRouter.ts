@@ -0,0 +1,34 @@
+import express, { NextFunction, Request, Response, Router } from 'express';
+
+const REGISTER_METHODS = ['all', 'get', 'post', 'put', 'patch', 'delete', 'options', 'head', 'use'] as const;
+
+// Express 4 ignores the promise an async handler returns; pass its rejection to next() so
+// errorHandler answers with a JSON 500 (logged with the request id) instead of the request hanging
+const forwardRejections = (handler: unknown): unknown => {
+  if (Array.isArray(handler)) {
+    return handler.map(forwardRejections);
+  }
+  // Paths are left alone, and so are error handlers, which Express recognises by their four arguments
+  if (typeof handler !== 'function' || handler.length === 4) {
+    return handler;
+  }
+  return (req: Request, res: Response, next: NextFunction) => {
+    const result = handler(req, res, next);
+    if (result && typeof result.catch === 'function') {
+      result.catch(next);
+    }
+  };
+};
+
+/**
+ * An express.Router whose route and middleware registrations forward async handler rejections
+ * to next(), like Express 5 does.
+ */
+export const asyncRouter = (): Router => {
+  const router = express.Router();
+  for (const method of REGISTER_METHODS) {
+    const register = (router as any)[method].bind(router);
+    (router as any)[method] = (...args: unknown[]) => register(...args.map(forwardRejections));
+  }
+  return router;
+};
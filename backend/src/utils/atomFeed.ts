@@ -0,0 +1,46 @@
+interface FeedRoom {
+  id: string;
+  name: string;
+  link: string;
+  expiryDate: Date;
+  createdAt: Date;
+}
+
+const escapeXml = (value: string): string => value
+  .replace(/&/g, '&amp;')
+  .replace(/</g, '&lt;')
+  .replace(/>/g, '&gt;')
+  .replace(/"/g, '&quot;')
+  .replace(/'/g, '&apos;');
+
+/**
+ * Render rooms (newest first) as an Atom 1.0 feed.
+ * Each entry's id is the room's permanent link, so feed readers never see a room twice.
+ */
+export const buildRoomFeed = (rooms: FeedRoom[], feedUrl: string): string => {
+  const updated = rooms.length > 0 ? rooms[0].createdAt : new Date(0);
+
+  const entries = rooms.map(room => [
+    '  <entry>',
+    `    <id>${escapeXml(room.link)}</id>`,
+    `    <title>${escapeXml(room.name)}</title>`,
+    `    <link rel="alternate" href="${escapeXml(room.link)}"/>`,
+    `    <published>${room.createdAt.toISOString()}</published>`,
+    `    <updated>${room.createdAt.toISOString()}</updated>`,
+    `    <summary>Room "${escapeXml(room.name)}" expires ${room.expiryDate.toISOString()}</summary>`,
+    '  </entry>',
+  ].join('\n'));
+
+  return [
+    '<?xml version="1.0" encoding="utf-8"?>',
+    '<feed xmlns="http://www.w3.org/2005/Atom">',
+    `  <id>${escapeXml(feedUrl)}</id>`,
+    '  <title>ColourStream rooms</title>',
+    `  <link rel="self" href="${escapeXml(feedUrl)}"/>`,
+    `  <updated>${updated.toISOString()}</updated>`,
+    '  <author><name>ColourStream</name></author>',
+    ...entries,
+    '</feed>',
+    '',
+  ].join('\n');
+};
@@ -0,0 +1,107 @@
+import { serverConfig } from '../config/serverConfig';
+
+interface BoundedCacheOptions {
+  // Least recently used entries are evicted beyond this many
+  maxEntries?: number;
+  // Entries expire this long after they were last set
+  ttlMs?: number;
+  // How often expired entries are swept out, 0 disables the sweep (expiry is still checked on read)
+  sweepIntervalMs?: number;
+  // Clock, replaceable in tests
+  now?: () => number;
+}
+
+interface Entry<V> {
+  value: V;
+  expiresAt: number;
+}
+
+const DEFAULT_SWEEP_INTERVAL_MS = 60 * 1000;
+
+/**
+ * A Map-like cache with a size cap (LRU eviction) and a TTL, for in-memory state that
+ * would otherwise grow for as long as the process runs. Only use it where losing an
+ * entry is safe, i.e. the value can be recomputed or refetched.
+ */
+export class BoundedCache<K, V> {
+  private entries = new Map<K, Entry<V>>();
+  private readonly maxEntries: number;
+  private readonly ttlMs: number;
+  private readonly now: () => number;
+  private sweeper?: NodeJS.Timeout;
+
+  constructor({
+    maxEntries = serverConfig.cacheMaxEntries,
+    ttlMs = serverConfig.cacheTtlMs,
+    sweepIntervalMs = DEFAULT_SWEEP_INTERVAL_MS,
+    now = Date.now,
+  }: BoundedCacheOptions = {}) {
+    this.maxEntries = maxEntries;
+    this.ttlMs = ttlMs;
+    this.now = now;
+
+    if (sweepIntervalMs > 0) {
+      this.sweeper = setInterval(() => this.sweep(), sweepIntervalMs);
+      this.sweeper.unref(); // Don't keep the process alive just for the sweep
+    }
+  }
+
+  get size(): number {
+    return this.entries.size;
+  }
+
+  get(key: K): V | undefined {
+    const entry = this.entries.get(key);
+    if (!entry) {
+      return undefined;
+    }
+    if (entry.expiresAt <= this.now()) {
+      this.entries.delete(key);
+      return undefined;
+    }
+    // Re-insert so Map order tracks recency
+    this.entries.delete(key);
+    this.entries.set(key, entry);
+    return entry.value;
+  }
+
+  has(key: K): boolean {
+    const entry = this.entries.get(key);
+    return entry !== undefined && entry.expiresAt > this.now();
+  }
+
+  set(key: K, value: V): this {
+    this.entries.delete(key);
+    this.entries.set(key, { value, expiresAt: this.now() + this.ttlMs });
+
+    while (this.entries.size > this.maxEntries) {
+      const oldest = this.entries.keys().next().value as K;
+      this.entries.delete(oldest);
+    }
+    return this;
+  }
+
+  delete(key: K): boolean {
+    return this.entries.delete(key);
+  }
+
+  // Drop expired entries, returning how many were removed
+  sweep(): number {
+    const now = this.now();
+    let removed = 0;
+    for (const [key, entry] of this.entries) {
+      if (entry.expiresAt <= now) {
+        this.entries.delete(key);
+        removed++;
+      }
+    }
+    return removed;
+  }
+
+  stop(): void {
+    if (this.sweeper) {
+      clearInterval(this.sweeper);
+      this.sweeper = undefined;
+    }
+  }
+}
@@ -0,0 +1,126 @@
+import { dbConfig } from '../config/dbConfig';
+import { logger } from './logger';
+
+type BreakerState = 'closed' | 'open' | 'half_open';
+
+interface CircuitBreakerOptions {
+  name: string;
+  failureThreshold: number;
+  windowMs: number;
+  cooldownMs: number;
+}
+
+/**
+ * Stops calls to a failing dependency for a cooldown period.
+ * closed: calls pass, consecutive failures within the window are counted.
+ * open: calls are refused until the cooldown elapses.
+ * half_open: a single probe call passes and the rest are refused until it settles; a success closes
+ * the breaker, a failure reopens it. A probe that never settles (a request that ran no query) is
+ * given up after another cooldown, and the next call becomes the probe.
+ */
+class CircuitBreaker {
+  private state: BreakerState = 'closed';
+  private consecutiveFailures = 0;
+  private firstFailureAt = 0;
+  private openedAt = 0;
+  private probeStartedAt = 0;
+  private timesOpened = 0;
+
+  constructor(private readonly options: CircuitBreakerOptions) {}
+
+  // Whether a call may go ahead right now
+  allowRequest(): boolean {
+    if (this.state === 'closed') {
+      return true;
+    }
+
+    const now = Date.now();
+    if (this.state === 'open') {
+      if (now - this.openedAt < this.options.cooldownMs) {
+        return false;
+      }
+      this.transition('half_open');
+    } else if (now - this.probeStartedAt < this.options.cooldownMs) {
+      // The probe is still in flight
+      return false;
+    }
+    this.probeStartedAt = now;
+    return true;
+  }
+
+  recordSuccess(): void {
+    this.consecutiveFailures = 0;
+    if (this.state !== 'closed') {
+      this.transition('closed');
+    }
+  }
+
+  recordFailure(): void {
+    const now = Date.now();
+
+    if (this.state === 'half_open') {
+      this.open(now);
+      return;
+    }
+
+    // Start a new count when the previous failures fall outside the window
+    if (this.consecutiveFailures === 0 || now - this.firstFailureAt > this.options.windowMs) {
+      this.consecutiveFailures = 0;
+      this.firstFailureAt = now;
+    }
+    this.consecutiveFailures += 1;
+
+    if (this.state === 'closed' && this.consecutiveFailures >= this.options.failureThreshold) {
+      this.open(now);
+    }
+  }
+
+  // Seconds until a trial request will be allowed, for Retry-After
+  retryAfterSeconds(): number {
+    if (this.state === 'closed') {
+      return 1;
+    }
+    const since = this.state === 'open' ? this.openedAt : this.probeStartedAt;
+    const remainingMs = this.options.cooldownMs - (Date.now() - since);
+    return Math.max(1, Math.ceil(remainingMs / 1000));
+  }
+
+  getStatus() {
+    return {
+      state: this.state,
+      consecutiveFailures: this.consecutiveFailures,
+      timesOpened: this.timesOpened,
+      openedAt: this.openedAt ? new Date(this.openedAt).toISOString() : null,
+    };
+  }
+
+  private open(now: number): void {
+    this.openedAt = now;
+    this.timesOpened += 1;
+    this.transition('open');
+  }
+
+  private transition(next: BreakerState): void {
+    logger.warn(`${this.options.name} circuit breaker ${this.state} -> ${next}`, {
+      consecutiveFailures: this.consecutiveFailures,
+    });
+    this.state = next;
+  }
+}
+
+// Guards the database: opened by connection-level Prisma failures, checked before each API request
+export const dbCircuitBreaker = new CircuitBreaker({
+  name: 'Database',
+  failureThreshold: dbConfig.breakerFailureThreshold,
+  windowMs: dbConfig.breakerWindowMs,
+  cooldownMs: dbConfig.breakerCooldownMs,
+});
+
+// Guards the DATABASE_READ_URL replica on its own, so a failing replica sends reads to the primary
+// instead of opening dbCircuitBreaker and refusing the whole API
+export const dbReadCircuitBreaker = new CircuitBreaker({
+  name: 'Read replica',
+  failureThreshold: dbConfig.breakerFailureThreshold,
+  windowMs: dbConfig.breakerWindowMs,
+  cooldownMs: dbConfig.breakerCooldownMs,
+});
@@ -0,0 +1,134 @@
+import fs from 'fs';
+import path from 'path';
+import dotenv from 'dotenv';
+import { isDeepStrictEqual } from 'util';
+import { loadCorsConfig, corsConfig } from '../config/corsConfig';
+import { loadDbConfig, dbConfig } from '../config/dbConfig';
+import { loadRateLimitConfig, rateLimitConfig } from '../config/rateLimitConfig';
+import { loadServerConfig, serverConfig } from '../config/serverConfig';
+import { logger, reloadLogLevel } from './logger';
+
+interface ReloadableConfig {
+  name: string;
+  current: Record<string, unknown>;
+  load: () => Record<string, unknown>;
+  // Settings every consumer reads per request; anything else needs a restart to change
+  hotKeys: string[];
+}
+
+const RELOADABLE_CONFIGS: ReloadableConfig[] = [
+  {
+    name: 'server',
+    current: serverConfig,
+    load: loadServerConfig,
+    hotKeys: [
+      'readOnly', 'prettyJson', 'optionsAllow', 'trailingSlash', 'forceHttps', 'forceHttpsMode',
+      'accessLog', 'accessLogExcludePaths', 'maxConcurrentRequests', 'retryAfterJitterMs',
+      'requestTimeoutMs', 'routeTimeouts',
+    ],
+  },
+  { name: 'rateLimits', current: rateLimitConfig, load: loadRateLimitConfig, hotKeys: ['keyStrategy', 'ipMax', 'userMax', 'loginBypassCidrs'] },
+  { name: 'cors', current: corsConfig, load: loadCorsConfig, hotKeys: ['maxAge', 'allowedOrigins'] },
+  { name: 'database', current: dbConfig, load: loadDbConfig, hotKeys: [] },
+];
+
+// Read only when the process starts; a changed value is reported but not applied
+const RESTART_ONLY_ENV = ['DATABASE_URL', 'BASE_PATH'];
+
+interface ConfigChange {
+  key: string;
+  from: unknown;
+  to: unknown;
+}
+
+export interface ReloadResult {
+  applied: ConfigChange[];
+  restartRequired: string[];
+}
+
+const envFilePath = path.resolve(process.cwd(), '.env');
+
+const readEnvFile = (): Record<string, string> =>
+  fs.existsSync(envFilePath) ? dotenv.parse(fs.readFileSync(envFilePath)) : {};
+
+// What the .env file supplied last time. dotenv never overrides the real environment, so a variable
+// is only taken from the file if it is unset or still holds the file's previous value.
+let fileValues = readEnvFile();
+
+const applyEnvFile = (next: Record<string, string>) => {
+  for (const key of new Set([...Object.keys(fileValues), ...Object.keys(next)])) {
+    const ownedByFile = process.env[key] === undefined || process.env[key] === fileValues[key];
+    if (!ownedByFile) {
+      continue;
+    }
+    if (next[key] === undefined) {
+      delete process.env[key];
+    } else {
+      process.env[key] = next[key];
+    }
+  }
+  fileValues = next;
+};
+
+/**
+ * Re-read .env and apply the hot-reloadable settings (rate limits, log level, CORS, feature
+ * toggles) in place. Every config is rebuilt before any is applied, so a reload either takes
+ * effect as a whole or, if a value is fatal (e.g. a bad PORT), not at all.
+ */
+export const reloadConfig = (): ReloadResult => {
+  const previousEnv = { ...process.env };
+  const previousFileValues = fileValues;
+  const previousLogLevel = logger.level;
+
+  let loaded: Record<string, unknown>[];
+  try {
+    applyEnvFile(readEnvFile());
+    loaded = RELOADABLE_CONFIGS.map(config => config.load());
+  } catch (error) {
+    // Put the environment back so the running settings and process.env still agree
+    for (const key of Object.keys(process.env)) {
+      if (!(key in previousEnv)) {
+        delete process.env[key];
+      }
+    }
+    Object.assign(process.env, previousEnv);
+    fileValues = previousFileValues;
+    throw error;
+  }
+
+  const result: ReloadResult = { applied: [], restartRequired: [] };
+
+  RELOADABLE_CONFIGS.forEach((config, index) => {
+    const next = loaded[index];
+    for (const key of Object.keys(next)) {
+      if (isDeepStrictEqual(config.current[key], next[key])) {
+        continue;
+      }
+      const qualified = `${config.name}.${key}`;
+      if (config.hotKeys.includes(key)) {
+        result.applied.push({ key: qualified, from: config.current[key], to: next[key] });
+        config.current[key] = next[key];
+      } else {
+        result.restartRequired.push(qualified);
+      }
+    }
+  });
+
+  for (const name of RESTART_ONLY_ENV) {
+    if (process.env[name] !== previousEnv[name]) {
+      result.restartRequired.push(name);
+    }
+  }
+
+  const logLevel = reloadLogLevel();
+  if (logLevel !== previousLogLevel) {
+    result.applied.push({ key: 'logLevel', from: previousLogLevel, to: logLevel });
+  }
+
+  // Secrets are left out of the log; only the setting names are reported for restart-only changes
+  logger.info('Configuration reloaded', { applied: result.applied });
+  if (result.restartRequired.length > 0) {
+    logger.warn('Configuration changes that need a restart were not applied', { settings: result.restartRequired });
+  }
+  return result;
+};
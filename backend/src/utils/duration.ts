@@ -0,0 +1,46 @@
+const UNIT_MS: Record<string, number> = {
+  ms: 1,
+  s: 1000,
+  m: 60 * 1000,
+  h: 60 * 60 * 1000,
+  d: 24 * 60 * 60 * 1000,
+};
+
+/**
+ * Parses a duration string such as "90s", "15m", "1h30m" or "7d"
+ * @returns The duration in milliseconds, or null if the value is malformed
+ */
+export function parseDuration(value: string): number | null {
+  const trimmed = value.trim();
+  if (!/^(\d+(\.\d+)?(ms|s|m|h|d))+$/.test(trimmed)) {
+    return null;
+  }
+
+  const pattern = /(\d+(?:\.\d+)?)(ms|s|m|h|d)/g;
+  let total = 0;
+  let match: RegExpExecArray | null;
+  while ((match = pattern.exec(trimmed)) !== null) {
+    total += Number(match[1]) * UNIT_MS[match[2]];
+  }
+  return total;
+}
+
+/**
+ * Formats milliseconds as a duration string such as "3d4h5m6s", the inverse of parseDuration
+ * (sub-second precision is dropped)
+ */
+export function formatDuration(ms: number): string {
+  let seconds = Math.max(0, Math.floor(ms / 1000));
+  const parts: string[] = [];
+
+  for (const [unit, size] of [['d', 86400], ['h', 3600], ['m', 60]] as const) {
+    if (seconds >= size) {
+      parts.push(`${Math.floor(seconds / size)}${unit}`);
+      seconds %= size;
+    }
+  }
+  if (seconds > 0 || parts.length === 0) {
+    parts.push(`${seconds}s`);
+  }
+  return parts.join('');
+}
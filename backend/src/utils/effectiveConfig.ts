@@ -0,0 +1,132 @@
+import { authConfig } from '../config/authConfig';
+import { backupConfig } from '../config/backupConfig';
+import { corsConfig } from '../config/corsConfig';
+import { dbConfig } from '../config/dbConfig';
+import { rateLimitConfig } from '../config/rateLimitConfig';
+import { roomConfig } from '../config/roomConfig';
+import { secrets } from '../config/secrets';
+import { serverConfig } from '../config/serverConfig';
+import { telegramConfig } from '../config/telegramConfig';
+import { logger } from './logger';
+
+const REDACTED = '***';
+
+// Environment variables holding credentials; only whether they are set is ever reported
+const SECRET_ENV_VARS = [
+  'ADMIN_AUTH_SECRET',
+  'JWT_KEY',
+  'JWT_SECRET',
+  'ADMIN_PASSWORD',
+  'HOST_USERS',
+  'OIDC_CLIENT_SECRET',
+  'OME_API_ACCESS_TOKEN',
+  'OME_WEBHOOK_SECRET',
+  'OME_SIGNATURE_SECRET',
+  'TELEGRAM_BOT_TOKEN',
+];
+
+const redact = (value: string | undefined): string | null => (value ? REDACTED : null);
+
+// Report the database driver only; the URL itself carries credentials
+const databaseDriver = (): string | null => {
+  const url = process.env.DATABASE_URL;
+  if (!url) {
+    return null;
+  }
+  const scheme = url.split(':')[0];
+  return scheme || null;
+};
+
+/**
+ * Build a snapshot of the configuration the server is actually running with.
+ * Every value is picked explicitly, so an env var never lands in the output by accident;
+ * secrets appear as "***" when set and null when not.
+ */
+export const getEffectiveConfig = () => ({
+  server: {
+    nodeEnv: process.env.NODE_ENV || 'development',
+    port: serverConfig.port,
+    forceHttps: serverConfig.forceHttps,
+    forceHttpsMode: serverConfig.forceHttpsMode,
+    readOnly: serverConfig.readOnly,
+    accessLog: serverConfig.accessLog,
+    accessLogExcludePaths: serverConfig.accessLogExcludePaths,
+    basePath: process.env.BASE_PATH || '/api',
+    logLevel: logger.level,
+    shutdownGracePeriodMs: serverConfig.shutdownGracePeriodMs,
+    selfTest: serverConfig.selfTest,
+    maxConcurrentRequests: serverConfig.maxConcurrentRequests,
+    maxWebSocketClients: serverConfig.maxWebSocketClients,
+    cacheMaxEntries: serverConfig.cacheMaxEntries,
+    cacheTtlMs: serverConfig.cacheTtlMs,
+    retryAfterJitterMs: serverConfig.retryAfterJitterMs,
+    requestTimeoutMs: serverConfig.requestTimeoutMs,
+    routeTimeouts: serverConfig.routeTimeouts,
+  },
+  database: {
+    driver: databaseDriver(),
+    readReplica: Boolean(dbConfig.readUrl),
+    breakerFailureThreshold: dbConfig.breakerFailureThreshold,
+    breakerWindowMs: dbConfig.breakerWindowMs,
+    breakerCooldownMs: dbConfig.breakerCooldownMs,
+    slowQueryThresholdMs: dbConfig.slowQueryThresholdMs,
+  },
+  auth: {
+    tokenTtlSeconds: authConfig.tokenTtlSeconds,
+    refreshTtlSeconds: authConfig.refreshTtlSeconds,
+    loginFailureDelayMs: authConfig.loginFailureDelayMs,
+    loginFailureDelayMaxMs: authConfig.loginFailureDelayMaxMs,
+    revocationCacheTtlMs: authConfig.revocationCacheTtlMs,
+    oidcEnabled: process.env.OIDC_ENABLED === 'true',
+    webauthnRpId: process.env.WEBAUTHN_RP_ID || null,
+  },
+  cors: {
+    maxAge: corsConfig.maxAge,
+    frontendUrl: process.env.FRONTEND_URL || null,
+    allowedOrigins: corsConfig.allowedOrigins,
+  },
+  rateLimits: {
+    keyStrategy: rateLimitConfig.keyStrategy,
+    ipMax: rateLimitConfig.ipMax,
+    userMax: rateLimitConfig.userMax,
+    loginBypassCidrs: rateLimitConfig.loginBypassCidrs,
+  },
+  rooms: {
+    joinRateLimitPerSecond: roomConfig.joinRateLimitPerSecond,
+    maxBatchIds: roomConfig.maxBatchIds,
+    bulkCreateMax: roomConfig.bulkCreateMax,
+    reconcileConfirmDeletes: roomConfig.reconcileConfirmDeletes,
+    defaultPageSize: roomConfig.defaultPageSize,
+    maxPageSize: roomConfig.maxPageSize,
+    pageSizeOverflow: roomConfig.pageSizeOverflow,
+    fullTextSearch: roomConfig.fullTextSearch,
+    feedSize: roomConfig.feedSize,
+    nameTemplate: roomConfig.nameTemplate,
+    minNameLength: roomConfig.minNameLength,
+    reservedNames: roomConfig.reservedNames,
+    groupByKeys: roomConfig.groupByKeys,
+    shareLinkTtlMs: roomConfig.shareLinkTtlMs,
+    shareLinkMaxTtlMs: roomConfig.shareLinkMaxTtlMs,
+    joinUrlTemplate: roomConfig.joinUrlTemplate || null,
+    settingsCapabilitiesUrl: roomConfig.settingsCapabilitiesUrl || null,
+    settingsCapabilitiesTtlMs: roomConfig.settingsCapabilitiesTtlMs,
+    settingsAllowlist: roomConfig.settingsAllowlist,
+  },
+  backups: {
+    directory: backupConfig.directory,
+    intervalMinutes: backupConfig.intervalMinutes,
+  },
+  features: {
+    telegram: telegramConfig.enabled,
+    backupScheduler: backupConfig.intervalMinutes > 0,
+    faviconNoContent: serverConfig.faviconNoContent,
+    trailingSlash: serverConfig.trailingSlash,
+    prettyJson: serverConfig.prettyJson,
+    optionsAllow: serverConfig.optionsAllow,
+  },
+  secretsProvider: secrets.name,
+  secrets: SECRET_ENV_VARS.reduce<Record<string, string | null>>((values, name) => {
+    values[name] = redact(secrets.get(name));
+    return values;
+  }, {}),
+});
@@ -0,0 +1,43 @@
+import { parseDuration } from './duration';
+
+interface EnvNumberOptions {
+  // Also accept 0, which settings use to mean "disabled"
+  allowZero?: boolean;
+}
+
+/**
+ * Read an integer setting that must be positive (or zero, with allowZero).
+ * Unset or blank uses the fallback; a malformed value warns and uses the fallback.
+ */
+export const envPositiveInt = (name: string, fallback: number, { allowZero = false }: EnvNumberOptions = {}): number => {
+  const value = process.env[name];
+  if (value === undefined || value.trim() === '') {
+    return fallback;
+  }
+
+  const parsed = Number(value.trim());
+  if (!Number.isInteger(parsed) || parsed < 0 || (parsed === 0 && !allowZero)) {
+    console.warn(`Invalid ${name} "${value}", falling back to ${fallback}.`);
+    return fallback;
+  }
+  return parsed;
+};
+
+/**
+ * Read a duration setting such as 30s or 24h in milliseconds. It must be positive, or zero with
+ * allowZero ("0" is accepted without a unit). Unset or blank uses the fallback; a malformed value
+ * warns and uses the fallback.
+ */
+export const envDurationMs = (name: string, fallback: string, { allowZero = false }: EnvNumberOptions = {}): number => {
+  const value = process.env[name];
+  if (value === undefined || value.trim() === '') {
+    return parseDuration(fallback)!;
+  }
+
+  const ms = allowZero && value.trim() === '0' ? 0 : parseDuration(value);
+  if (ms === null || (ms === 0 && !allowZero)) {
+    console.warn(`Invalid ${name} "${value}", falling back to ${fallback}.`);
+    return parseDuration(fallback)!;
+  }
+  return ms;
+};
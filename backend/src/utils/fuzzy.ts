@@ -0,0 +1,47 @@
+// Edit distance between two strings (insertions, deletions and substitutions each cost 1)
+const levenshtein = (a: string, b: string): number => {
+  if (a === b) return 0;
+  if (a.length === 0) return b.length;
+  if (b.length === 0) return a.length;
+
+  let previous = Array.from({ length: b.length + 1 }, (_, i) => i);
+  for (let i = 1; i <= a.length; i++) {
+    const current = [i];
+    for (let j = 1; j <= b.length; j++) {
+      const cost = a[i - 1] === b[j - 1] ? 0 : 1;
+      current[j] = Math.min(previous[j] + 1, current[j - 1] + 1, previous[j - 1] + cost);
+    }
+    previous = current;
+  }
+  return previous[b.length];
+};
+
+const normalize = (value: string): string => value.toLowerCase().trim();
+
+const ratio = (a: string, b: string): number => {
+  const longest = Math.max(a.length, b.length);
+  return longest === 0 ? 1 : 1 - levenshtein(a, b) / longest;
+};
+
+/**
+ * Score how well a query matches a name, from 0 (unrelated) to 1 (identical).
+ * The query is compared against the whole name and against each word in it,
+ * so "studoi" still finds "Main Studio A". Substring matches score highly.
+ */
+export const similarity = (query: string, name: string): number => {
+  const q = normalize(query);
+  const n = normalize(name);
+  if (!q || !n) {
+    return 0;
+  }
+  if (q === n) {
+    return 1;
+  }
+  if (n.includes(q)) {
+    return 0.9 + 0.1 * (q.length / n.length);
+  }
+
+  const words = n.split(/[\s._-]+/).filter(Boolean);
+  const bestWord = words.reduce((best, word) => Math.max(best, ratio(q, word)), 0);
+  return Math.max(ratio(q, n), bestWord * 0.9);
+};
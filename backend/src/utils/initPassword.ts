@@ -1,18 +1,19 @@
 import { logger } from './logger';
+import { secrets } from '../config/secrets';
 
 export const initializePassword = async () => {
     try {
         logger.info('Initializing authentication system');
         
         // Check for required WebAuthn configuration
-        if (!process.env.WEBAUTHN_RP_ID || !process.env.JWT_SECRET) {
+        if (!process.env.WEBAUTHN_RP_ID || !secrets.get('JWT_SECRET')) {
             throw new Error('Required WebAuthn configuration missing');
         }
 
         logger.info('Authentication system initialized successfully', {
             envVars: {
                 NODE_ENV: process.env.NODE_ENV,
-                hasJwtSecret: !!process.env.JWT_SECRET,
+                hasJwtSecret: !!secrets.get('JWT_SECRET'),
                 hasWebAuthnConfig: !!process.env.WEBAUTHN_RP_ID
             }
         });
@@ -1,10 +1,31 @@
 import winston from 'winston';
 import path from 'path';
+import dotenv from 'dotenv';
+
+// Load environment variables so LOG_LEVEL from .env is honoured at import time
+dotenv.config();
 
 const logDir = path.join(__dirname, '../../logs');
 
+const LOG_LEVELS = ['debug', 'info', 'warn', 'error'];
+const DEFAULT_LOG_LEVEL = 'info';
+
+// Resolve LOG_LEVEL, falling back to info for missing or unknown values
+const resolveLogLevel = (value: string | undefined): { level: string; invalid: boolean } => {
+  const normalized = value?.trim().toLowerCase();
+  if (!normalized) {
+    return { level: DEFAULT_LOG_LEVEL, invalid: false };
+  }
+  if (!LOG_LEVELS.includes(normalized)) {
+    return { level: DEFAULT_LOG_LEVEL, invalid: true };
+  }
+  return { level: normalized, invalid: false };
+};
+
+const { level: logLevel, invalid: invalidLogLevel } = resolveLogLevel(process.env.LOG_LEVEL);
+
 export const logger = winston.createLogger({
-  level: 'info',
+  level: logLevel,
   format: winston.format.combine(
     winston.format.timestamp(),
     winston.format.json()
@@ -24,4 +45,22 @@ export const logger = winston.createLogger({
       )
     })
   ]
-}); 
\ No newline at end of file
+});
+
+const warnInvalidLogLevel = () => {
+  logger.warn(`Invalid LOG_LEVEL "${process.env.LOG_LEVEL}", expected one of ${LOG_LEVELS.join(', ')}. Falling back to ${DEFAULT_LOG_LEVEL}.`);
+};
+
+if (invalidLogLevel) {
+  warnInvalidLogLevel();
+}
+
+// Re-read LOG_LEVEL (after a config reload) and apply it to the running logger
+export const reloadLogLevel = (): string => {
+  const { level, invalid } = resolveLogLevel(process.env.LOG_LEVEL);
+  if (invalid) {
+    warnInvalidLogLevel();
+  }
+  logger.level = level;
+  return level;
+};
@@ -0,0 +1,22 @@
+const isObject = (value: unknown): value is Record<string, unknown> =>
+  typeof value === 'object' && value !== null && !Array.isArray(value);
+
+/**
+ * Apply an RFC 7386 JSON Merge Patch: objects merge recursively, a null member
+ * removes that member, and any other value (arrays included) replaces the target.
+ */
+export const applyMergePatch = (target: unknown, patch: unknown): unknown => {
+  if (!isObject(patch)) {
+    return patch;
+  }
+
+  const result: Record<string, unknown> = isObject(target) ? { ...target } : {};
+  for (const [key, value] of Object.entries(patch)) {
+    if (value === null) {
+      delete result[key];
+    } else {
+      result[key] = applyMergePatch(result[key], value);
+    }
+  }
+  return result;
+};
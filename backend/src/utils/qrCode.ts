@@ -0,0 +1,451 @@
+import zlib from 'zlib';
+
+// Minimal QR Code encoder (ISO/IEC 18004): byte mode, error correction level M, versions 1-10.
+// Version 10 holds 213 bytes, far more than a room join URL needs.
+
+// Error correction codewords per block and number of blocks for level M, indexed by version
+const ECC_CODEWORDS_PER_BLOCK = [-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26];
+const NUM_ERROR_CORRECTION_BLOCKS = [-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5];
+const MAX_VERSION = 10;
+// Level M's two-bit format indicator
+const ECL_M_FORMAT_BITS = 0;
+
+const getBit = (value: number, index: number): boolean => ((value >>> index) & 1) !== 0;
+
+// Data and error correction modules left once the function patterns are placed
+const rawDataModules = (version: number): number => {
+  let result = (16 * version + 128) * version + 64;
+  if (version >= 2) {
+    const numAlign = Math.floor(version / 7) + 2;
+    result -= (25 * numAlign - 10) * numAlign - 55;
+    if (version >= 7) {
+      result -= 36;
+    }
+  }
+  return result;
+};
+
+const dataCodewords = (version: number): number =>
+  Math.floor(rawDataModules(version) / 8) - ECC_CODEWORDS_PER_BLOCK[version] * NUM_ERROR_CORRECTION_BLOCKS[version];
+
+// Multiplication in GF(2^8) modulo the QR polynomial x^8 + x^4 + x^3 + x^2 + 1
+const gfMultiply = (x: number, y: number): number => {
+  let z = 0;
+  for (let i = 7; i >= 0; i--) {
+    z = (z << 1) ^ ((z >>> 7) * 0x11d);
+    z ^= ((y >>> i) & 1) * x;
+  }
+  return z;
+};
+
+export const reedSolomonDivisor = (degree: number): number[] => {
+  const result = new Array<number>(degree).fill(0);
+  result[degree - 1] = 1;
+  let root = 1;
+  for (let i = 0; i < degree; i++) {
+    for (let j = 0; j < result.length; j++) {
+      result[j] = gfMultiply(result[j], root);
+      if (j + 1 < result.length) {
+        result[j] ^= result[j + 1];
+      }
+    }
+    root = gfMultiply(root, 0x02);
+  }
+  return result;
+};
+
+export const reedSolomonRemainder = (data: number[], divisor: number[]): number[] => {
+  const result = new Array<number>(divisor.length).fill(0);
+  for (const byte of data) {
+    const factor = byte ^ (result.shift() as number);
+    result.push(0);
+    divisor.forEach((coefficient, i) => {
+      result[i] ^= gfMultiply(coefficient, factor);
+    });
+  }
+  return result;
+};
+
+// 15-bit format information (level and mask) with its BCH error correction
+export const formatBits = (mask: number): number => {
+  const data = (ECL_M_FORMAT_BITS << 3) | mask;
+  let rem = data;
+  for (let i = 0; i < 10; i++) {
+    rem = (rem << 1) ^ ((rem >>> 9) * 0x537);
+  }
+  return ((data << 10) | rem) ^ 0x5412;
+};
+
+// Split the data into blocks, append each block's error correction and interleave the result
+const addEccAndInterleave = (data: number[], version: number): number[] => {
+  const numBlocks = NUM_ERROR_CORRECTION_BLOCKS[version];
+  const blockEccLength = ECC_CODEWORDS_PER_BLOCK[version];
+  const rawCodewords = Math.floor(rawDataModules(version) / 8);
+  const numShortBlocks = numBlocks - (rawCodewords % numBlocks);
+  const shortBlockLength = Math.floor(rawCodewords / numBlocks);
+
+  const divisor = reedSolomonDivisor(blockEccLength);
+  const blocks: number[][] = [];
+  for (let i = 0, k = 0; i < numBlocks; i++) {
+    const block = data.slice(k, k + shortBlockLength - blockEccLength + (i < numShortBlocks ? 0 : 1));
+    k += block.length;
+    const ecc = reedSolomonRemainder(block, divisor);
+    if (i < numShortBlocks) {
+      block.push(0);
+    }
+    blocks.push(block.concat(ecc));
+  }
+
+  const result: number[] = [];
+  for (let i = 0; i < blocks[0].length; i++) {
+    blocks.forEach((block, j) => {
+      // Skip the padding byte added to short blocks
+      if (i !== shortBlockLength - blockEccLength || j >= numShortBlocks) {
+        result.push(block[i]);
+      }
+    });
+  }
+  return result;
+};
+
+// Mode indicator, length, payload, terminator and pad bytes, packed into codewords
+const encodeData = (bytes: Buffer, version: number): number[] => {
+  const bits: number[] = [];
+  const append = (value: number, length: number) => {
+    for (let i = length - 1; i >= 0; i--) {
+      bits.push((value >>> i) & 1);
+    }
+  };
+
+  append(0x4, 4);
+  append(bytes.length, version <= 9 ? 8 : 16);
+  for (const byte of bytes) {
+    append(byte, 8);
+  }
+
+  const capacityBits = dataCodewords(version) * 8;
+  append(0, Math.min(4, capacityBits - bits.length));
+  append(0, (8 - (bits.length % 8)) % 8);
+  for (let pad = 0xec; bits.length < capacityBits; pad ^= 0xec ^ 0x11) {
+    append(pad, 8);
+  }
+
+  const codewords: number[] = [];
+  for (let i = 0; i < bits.length; i += 8) {
+    codewords.push(bits.slice(i, i + 8).reduce((byte, bit) => (byte << 1) | bit, 0));
+  }
+  return codewords;
+};
+
+const alignmentPatternPositions = (version: number, size: number): number[] => {
+  if (version === 1) {
+    return [];
+  }
+  const numAlign = Math.floor(version / 7) + 2;
+  const step = Math.ceil((version * 4 + 4) / (numAlign * 2 - 2)) * 2;
+  const result = [6];
+  for (let pos = size - 7; result.length < numAlign; pos -= step) {
+    result.splice(1, 0, pos);
+  }
+  return result;
+};
+
+const MASKS: Array<(x: number, y: number) => boolean> = [
+  (x, y) => (x + y) % 2 === 0,
+  (_x, y) => y % 2 === 0,
+  (x) => x % 3 === 0,
+  (x, y) => (x + y) % 3 === 0,
+  (x, y) => (Math.floor(x / 3) + Math.floor(y / 2)) % 2 === 0,
+  (x, y) => ((x * y) % 2) + ((x * y) % 3) === 0,
+  (x, y) => (((x * y) % 2) + ((x * y) % 3)) % 2 === 0,
+  (x, y) => (((x + y) % 2) + ((x * y) % 3)) % 2 === 0,
+];
+
+class QrMatrix {
+  readonly modules: boolean[][];
+  private readonly isFunction: boolean[][];
+
+  constructor(readonly version: number) {
+    const size = this.size;
+    this.modules = Array.from({ length: size }, () => new Array<boolean>(size).fill(false));
+    this.isFunction = Array.from({ length: size }, () => new Array<boolean>(size).fill(false));
+    this.drawFunctionPatterns();
+  }
+
+  get size(): number {
+    return this.version * 4 + 17;
+  }
+
+  private setFunction(x: number, y: number, dark: boolean) {
+    this.modules[y][x] = dark;
+    this.isFunction[y][x] = true;
+  }
+
+  private drawFunctionPatterns() {
+    const size = this.size;
+    for (let i = 0; i < size; i++) {
+      this.setFunction(6, i, i % 2 === 0);
+      this.setFunction(i, 6, i % 2 === 0);
+    }
+
+    for (const [cx, cy] of [[3, 3], [size - 4, 3], [3, size - 4]]) {
+      for (let dy = -4; dy <= 4; dy++) {
+        for (let dx = -4; dx <= 4; dx++) {
+          const x = cx + dx;
+          const y = cy + dy;
+          const distance = Math.max(Math.abs(dx), Math.abs(dy));
+          if (x >= 0 && x < size && y >= 0 && y < size) {
+            this.setFunction(x, y, distance !== 2 && distance !== 4);
+          }
+        }
+      }
+    }
+
+    const positions = alignmentPatternPositions(this.version, size);
+    const last = positions.length - 1;
+    positions.forEach((cx, i) => {
+      positions.forEach((cy, j) => {
+        // The three corners are taken by finder patterns
+        if ((i === 0 && j === 0) || (i === 0 && j === last) || (i === last && j === 0)) {
+          return;
+        }
+        for (let dy = -2; dy <= 2; dy++) {
+          for (let dx = -2; dx <= 2; dx++) {
+            this.setFunction(cx + dx, cy + dy, Math.max(Math.abs(dx), Math.abs(dy)) !== 1);
+          }
+        }
+      });
+    });
+
+    // Reserve the format areas now; the real bits are drawn once the mask is chosen
+    this.drawFormatBits(0);
+    this.drawVersion();
+  }
+
+  drawFormatBits(mask: number) {
+    const bits = formatBits(mask);
+    const size = this.size;
+    for (let i = 0; i <= 5; i++) {
+      this.setFunction(8, i, getBit(bits, i));
+    }
+    this.setFunction(8, 7, getBit(bits, 6));
+    this.setFunction(8, 8, getBit(bits, 7));
+    this.setFunction(7, 8, getBit(bits, 8));
+    for (let i = 9; i < 15; i++) {
+      this.setFunction(14 - i, 8, getBit(bits, i));
+    }
+    for (let i = 0; i < 8; i++) {
+      this.setFunction(size - 1 - i, 8, getBit(bits, i));
+    }
+    for (let i = 8; i < 15; i++) {
+      this.setFunction(8, size - 15 + i, getBit(bits, i));
+    }
+    // The dark module is always set
+    this.setFunction(8, size - 8, true);
+  }
+
+  private drawVersion() {
+    if (this.version < 7) {
+      return;
+    }
+    let rem = this.version;
+    for (let i = 0; i < 12; i++) {
+      rem = (rem << 1) ^ ((rem >>> 11) * 0x1f25);
+    }
+    const bits = (this.version << 12) | rem;
+    for (let i = 0; i < 18; i++) {
+      const a = this.size - 11 + (i % 3);
+      const b = Math.floor(i / 3);
+      this.setFunction(a, b, getBit(bits, i));
+      this.setFunction(b, a, getBit(bits, i));
+    }
+  }
+
+  // Place codewords in the two-column zigzag from the bottom-right corner, skipping the timing column
+  drawCodewords(data: number[]) {
+    const size = this.size;
+    let i = 0;
+    for (let right = size - 1; right >= 1; right -= 2) {
+      if (right === 6) {
+        right = 5;
+      }
+      for (let vert = 0; vert < size; vert++) {
+        for (let j = 0; j < 2; j++) {
+          const x = right - j;
+          const upward = ((right + 1) & 2) === 0;
+          const y = upward ? size - 1 - vert : vert;
+          if (!this.isFunction[y][x] && i < data.length * 8) {
+            this.modules[y][x] = getBit(data[i >>> 3], 7 - (i & 7));
+            i++;
+          }
+        }
+      }
+    }
+  }
+
+  // Masking is an XOR, so applying the same mask twice undoes it
+  applyMask(mask: number) {
+    const invert = MASKS[mask];
+    for (let y = 0; y < this.size; y++) {
+      for (let x = 0; x < this.size; x++) {
+        if (!this.isFunction[y][x] && invert(x, y)) {
+          this.modules[y][x] = !this.modules[y][x];
+        }
+      }
+    }
+  }
+
+  // Penalty rules 1 (runs of one colour), 2 (2x2 blocks) and 4 (dark/light balance). Rule 3
+  // (finder-like patterns) is left out; any mask decodes, the score only picks a cleaner one.
+  penalty(): number {
+    const size = this.size;
+    let result = 0;
+    for (let a = 0; a < size; a++) {
+      let rowRun = 1;
+      let colRun = 1;
+      for (let b = 1; b <= size; b++) {
+        if (b < size && this.modules[a][b] === this.modules[a][b - 1]) {
+          rowRun++;
+        } else {
+          result += rowRun >= 5 ? rowRun - 2 : 0;
+          rowRun = 1;
+        }
+        if (b < size && this.modules[b][a] === this.modules[b - 1][a]) {
+          colRun++;
+        } else {
+          result += colRun >= 5 ? colRun - 2 : 0;
+          colRun = 1;
+        }
+      }
+    }
+
+    let dark = 0;
+    for (let y = 0; y < size; y++) {
+      for (let x = 0; x < size; x++) {
+        dark += this.modules[y][x] ? 1 : 0;
+        if (x < size - 1 && y < size - 1) {
+          const colour = this.modules[y][x];
+          if (colour === this.modules[y][x + 1] && colour === this.modules[y + 1][x] && colour === this.modules[y + 1][x + 1]) {
+            result += 3;
+          }
+        }
+      }
+    }
+    const total = size * size;
+    result += (Math.ceil(Math.abs(dark * 20 - total * 10) / total) - 1) * 10;
+    return result;
+  }
+}
+
+/**
+ * Encode text as a QR code, returning the module grid (true is dark) without the quiet zone.
+ * Throws if the text does not fit in version 10.
+ */
+export const encodeQrCode = (text: string): boolean[][] => {
+  const bytes = Buffer.from(text, 'utf8');
+  let version = 1;
+  // Byte mode: 4-bit mode indicator plus an 8-bit (versions 1-9) or 16-bit length
+  while (version <= MAX_VERSION && 4 + (version <= 9 ? 8 : 16) + bytes.length * 8 > dataCodewords(version) * 8) {
+    version++;
+  }
+  if (version > MAX_VERSION) {
+    throw new Error(`Text too long for a QR code: ${bytes.length} bytes`);
+  }
+
+  const matrix = new QrMatrix(version);
+  matrix.drawCodewords(addEccAndInterleave(encodeData(bytes, version), version));
+
+  let bestMask = 0;
+  let bestPenalty = Infinity;
+  for (let mask = 0; mask < MASKS.length; mask++) {
+    matrix.applyMask(mask);
+    matrix.drawFormatBits(mask);
+    const penalty = matrix.penalty();
+    if (penalty < bestPenalty) {
+      bestMask = mask;
+      bestPenalty = penalty;
+    }
+    matrix.applyMask(mask);
+  }
+  matrix.applyMask(bestMask);
+  matrix.drawFormatBits(bestMask);
+  return matrix.modules;
+};
+
+const QUIET_ZONE = 4;
+
+const CRC_TABLE = Array.from({ length: 256 }, (_, n) => {
+  let c = n;
+  for (let k = 0; k < 8; k++) {
+    c = c & 1 ? 0xedb88320 ^ (c >>> 1) : c >>> 1;
+  }
+  return c >>> 0;
+});
+
+const crc32 = (buffer: Buffer): number => {
+  let crc = 0xffffffff;
+  for (const byte of buffer) {
+    crc = CRC_TABLE[(crc ^ byte) & 0xff] ^ (crc >>> 8);
+  }
+  return (crc ^ 0xffffffff) >>> 0;
+};
+
+const pngChunk = (type: string, data: Buffer): Buffer => {
+  const length = Buffer.alloc(4);
+  length.writeUInt32BE(data.length);
+  const body = Buffer.concat([Buffer.from(type, 'ascii'), data]);
+  const crc = Buffer.alloc(4);
+  crc.writeUInt32BE(crc32(body));
+  return Buffer.concat([length, body, crc]);
+};
+
+// Render a QR code as a size x size greyscale PNG, including the standard 4-module quiet zone
+export const qrCodePng = (text: string, size: number): Buffer => {
+  const modules = encodeQrCode(text);
+  const dimension = modules.length + QUIET_ZONE * 2;
+
+  // One filter byte (0, none) ahead of each row of pixels
+  const raw = Buffer.alloc((size + 1) * size);
+  for (let py = 0; py < size; py++) {
+    const y = Math.floor((py * dimension) / size) - QUIET_ZONE;
+    for (let px = 0; px < size; px++) {
+      const x = Math.floor((px * dimension) / size) - QUIET_ZONE;
+      const dark = y >= 0 && y < modules.length && x >= 0 && x < modules.length && modules[y][x];
+      raw[py * (size + 1) + 1 + px] = dark ? 0x00 : 0xff;
+    }
+  }
+
+  const header = Buffer.alloc(13);
+  header.writeUInt32BE(size, 0);
+  header.writeUInt32BE(size, 4);
+  header[8] = 8; // bit depth
+  header[9] = 0; // greyscale
+  return Buffer.concat([
+    Buffer.from([0x89, 0x50, 0x4e, 0x47, 0x0d, 0x0a, 0x1a, 0x0a]),
+    pngChunk('IHDR', header),
+    pngChunk('IDAT', zlib.deflateSync(raw)),
+    pngChunk('IEND', Buffer.alloc(0)),
+  ]);
+};
+
+// Render a QR code as a square SVG of the given pixel size, with the standard 4-module quiet zone
+export const qrCodeSvg = (text: string, size: number): string => {
+  const modules = encodeQrCode(text);
+  const dimension = modules.length + QUIET_ZONE * 2;
+  const path: string[] = [];
+  modules.forEach((row, y) => {
+    row.forEach((dark, x) => {
+      if (dark) {
+        path.push(`M${x + QUIET_ZONE},${y + QUIET_ZONE}h1v1h-1z`);
+      }
+    });
+  });
+
+  return [
+    '<?xml version="1.0" encoding="UTF-8"?>',
+    `<svg xmlns="http://www.w3.org/2000/svg" width="${size}" height="${size}" viewBox="0 0 ${dimension} ${dimension}" shape-rendering="crispEdges">`,
+    `<rect width="${dimension}" height="${dimension}" fill="#fff"/>`,
+    `<path d="${path.join('')}" fill="#000"/>`,
+    '</svg>',
+  ].join('\n');
+};
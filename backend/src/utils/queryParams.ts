@@ -0,0 +1,77 @@
+import { Request } from 'express';
+import { AppError } from '../middleware/errorHandler';
+import { formatDuration, parseDuration } from './duration';
+
+interface RangeOptions {
+  min?: number;
+  max?: number;
+  // Out-of-range values are pulled into [min, max] instead of rejected; malformed values are always rejected
+  clamp?: boolean;
+}
+
+const describeRange = (kind: string, min?: string | number, max?: string | number): string => {
+  if (min !== undefined && max !== undefined) {
+    return `${kind} between ${min} and ${max}`;
+  }
+  if (min !== undefined) {
+    return `${kind} of at least ${min}`;
+  }
+  if (max !== undefined) {
+    return `${kind} of at most ${max}`;
+  }
+  return kind;
+};
+
+// Return value, or the nearer bound when clamping; otherwise an out-of-range value throws the given 400
+const applyRange = (value: number, { min, max, clamp = false }: RangeOptions, invalid: AppError): number => {
+  const belowMin = min !== undefined && value < min;
+  const aboveMax = max !== undefined && value > max;
+  if (!belowMin && !aboveMax) {
+    return value;
+  }
+  if (!clamp) {
+    throw invalid;
+  }
+  return belowMin ? min! : max!;
+};
+
+/**
+ * Read an integer query parameter, returning the default when it is absent.
+ * Malformed or out-of-range values throw a 400 naming the parameter and the accepted range.
+ */
+export const queryInt = (req: Request, name: string, defaultValue: number, options: RangeOptions = {}): number => {
+  const raw = req.query[name];
+  if (raw === undefined) {
+    return defaultValue;
+  }
+
+  const invalid = new AppError(400, `Invalid ${name}: expected ${describeRange('an integer', options.min, options.max)}`);
+  const value = typeof raw === 'string' && /^-?\d+$/.test(raw.trim()) ? Number(raw.trim()) : NaN;
+  if (!Number.isSafeInteger(value)) {
+    throw invalid;
+  }
+  return applyRange(value, options, invalid);
+};
+
+/**
+ * Read a duration query parameter such as 90s, 15m or 1h30m, in milliseconds, returning the
+ * default when it is absent. min and max are in milliseconds too. Malformed or out-of-range
+ * values throw a 400 naming the parameter and the accepted range.
+ */
+export const queryDuration = (req: Request, name: string, defaultMs: number, options: RangeOptions = {}): number => {
+  const raw = req.query[name];
+  if (raw === undefined) {
+    return defaultMs;
+  }
+
+  const { min, max } = options;
+  const range = min === undefined && max === undefined
+    ? 'a duration such as 30s or 2h'
+    : describeRange('a duration', min === undefined ? undefined : formatDuration(min), max === undefined ? undefined : formatDuration(max));
+  const invalid = new AppError(400, `Invalid ${name}: expected ${range}`);
+  const value = typeof raw === 'string' ? parseDuration(raw) : null;
+  if (value === null) {
+    throw invalid;
+  }
+  return applyRange(value, options, invalid);
+};
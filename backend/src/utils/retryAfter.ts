@@ -0,0 +1,10 @@
+import { serverConfig } from '../config/serverConfig';
+
+/**
+ * Retry-After value in whole seconds: the base delay plus a random spread of up to
+ * RETRY_AFTER_JITTER, so clients turned away together don't all come back at once.
+ */
+export const retryAfterSeconds = (baseSeconds: number): string => {
+  const jitterSeconds = Math.random() * (serverConfig.retryAfterJitterMs / 1000);
+  return String(Math.max(1, Math.ceil(baseSeconds + jitterSeconds)));
+};
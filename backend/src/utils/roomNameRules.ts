@@ -0,0 +1,24 @@
+import { roomConfig } from '../config/roomConfig';
+import { AppError } from '../middleware/errorHandler';
+
+interface RoomNameRules {
+  minNameLength: number;
+  reservedNames: string[];
+}
+
+/**
+ * Reject user-supplied room names that are too short or reserved (case-insensitive)
+ * with a 422, so the caller can tell a bad name apart from a malformed request.
+ */
+export const assertRoomNameAllowed = (name: string, rules: RoomNameRules = roomConfig): void => {
+  const trimmed = name.trim();
+
+  if (trimmed.length < rules.minNameLength) {
+    throw new AppError(422, `Room name must be at least ${rules.minNameLength} characters`);
+  }
+
+  const lowered = trimmed.toLowerCase();
+  if (rules.reservedNames.some(reserved => reserved.toLowerCase() === lowered)) {
+    throw new AppError(422, `Room name "${trimmed}" is reserved`);
+  }
+};
@@ -0,0 +1,64 @@
+import { randomBytes } from 'crypto';
+import { roomConfig } from '../config/roomConfig';
+import { nextSequenceValue, SequenceClient } from '../services/sequences';
+import { logger } from './logger';
+
+// Generated names may only contain letters, digits, spaces, dots, dashes and underscores
+const ROOM_NAME_PATTERN = /^[A-Za-z0-9 ._-]{1,100}$/;
+
+const PLACEHOLDERS: Record<string, string> = {
+  '{date}': 'current UTC date as YYYYMMDD',
+  '{rand}': 'six random lowercase letters/digits',
+  '{seq}': 'database-backed counter, unique across restarts and replicas',
+};
+
+const ROOM_NAME_SEQUENCE = 'room_name';
+
+const expandTemplate = (template: string, seq: number): string => {
+  const date = new Date().toISOString().slice(0, 10).replace(/-/g, '');
+  const rand = randomBytes(8).toString('base64').replace(/[^a-z0-9]/gi, '').toLowerCase().slice(0, 6);
+
+  return template
+    .replace(/\{date\}/g, date)
+    .replace(/\{rand\}/g, rand)
+    .replace(/\{seq\}/g, String(seq));
+};
+
+export const isValidRoomName = (name: string): boolean => ROOM_NAME_PATTERN.test(name);
+
+/**
+ * Expands a name pattern with the same placeholders as ROOM_NAME_TEMPLATE. {seq} draws from the
+ * shared room-name counter through the given client. The result is not validated.
+ */
+export const expandRoomNamePattern = async (pattern: string, client?: SequenceClient): Promise<string> => {
+  const seq = pattern.includes('{seq}') ? await nextSequenceValue(ROOM_NAME_SEQUENCE, client) : 0;
+  return expandTemplate(pattern, seq);
+};
+
+/**
+ * Generates a room name from ROOM_NAME_TEMPLATE for rooms created without an explicit name
+ */
+export const generateRoomName = async (): Promise<string> => {
+  const template = roomConfig.nameTemplate;
+  const name = await expandRoomNamePattern(template);
+
+  if (!isValidRoomName(name)) {
+    throw new Error(`ROOM_NAME_TEMPLATE "${template}" produced an invalid room name "${name}"`);
+  }
+  return name;
+};
+
+/**
+ * Logs the active template and its placeholders, and checks a sample expansion
+ */
+export const logRoomNameTemplate = (): void => {
+  const sample = expandTemplate(roomConfig.nameTemplate, 1);
+
+  logger.info(`Default room name template: "${roomConfig.nameTemplate}" (example: "${sample}")`, {
+    placeholders: PLACEHOLDERS,
+  });
+
+  if (!isValidRoomName(sample)) {
+    logger.warn(`ROOM_NAME_TEMPLATE produces names outside ${ROOM_NAME_PATTERN}; rooms created without a name will fail`);
+  }
+};
@@ -0,0 +1,87 @@
+import type { Room } from '@prisma/client';
+
+// Minimal protobuf encoder for the flat Room and RoomList messages in proto/room.proto:
+// only varints and length-delimited fields are needed.
+
+export const PROTOBUF_MEDIA_TYPE = 'application/x-protobuf';
+
+const WIRE_VARINT = 0;
+const WIRE_LENGTH_DELIMITED = 2;
+
+// Base-128, least significant group first; arithmetic rather than bit operations so values past 2^31
+// (timestamps in milliseconds) survive
+const varint = (value: number): Buffer => {
+  if (!Number.isSafeInteger(value) || value < 0) {
+    throw new RangeError(`Cannot encode ${value} as a varint`);
+  }
+  const bytes: number[] = [];
+  let rest = value;
+  while (rest >= 0x80) {
+    bytes.push((rest % 0x80) | 0x80);
+    rest = Math.floor(rest / 0x80);
+  }
+  bytes.push(rest);
+  return Buffer.from(bytes);
+};
+
+const key = (field: number, wireType: number): Buffer => varint(field * 8 + wireType);
+
+const varintField = (field: number, value: number): Buffer => Buffer.concat([key(field, WIRE_VARINT), varint(value)]);
+
+const bytesField = (field: number, value: Buffer): Buffer =>
+  Buffer.concat([key(field, WIRE_LENGTH_DELIMITED), varint(value.length), value]);
+
+const stringField = (field: number, value: string): Buffer => bytesField(field, Buffer.from(value, 'utf8'));
+
+type FieldKind = 'string' | 'int' | 'timestamp' | 'json';
+
+// Room column, field number and encoding; matches the Room message in proto/room.proto
+const ROOM_FIELDS: [keyof Room, number, FieldKind][] = [
+  ['id', 1, 'string'],
+  ['name', 2, 'string'],
+  ['mirotalkRoomId', 3, 'string'],
+  ['streamKey', 4, 'string'],
+  ['password', 5, 'string'],
+  ['displayPassword', 6, 'string'],
+  ['expiryDate', 7, 'timestamp'],
+  ['link', 8, 'string'],
+  ['presenterLink', 9, 'string'],
+  ['mirotalkToken', 10, 'string'],
+  ['settings', 11, 'json'],
+  ['availableFrom', 12, 'timestamp'],
+  ['availableUntil', 13, 'timestamp'],
+  ['version', 14, 'int'],
+  ['createdAt', 15, 'timestamp'],
+];
+
+const encodeField = (field: number, kind: FieldKind, value: unknown): Buffer => {
+  switch (kind) {
+    case 'string':
+      return stringField(field, String(value));
+    case 'int':
+      return varintField(field, Number(value));
+    case 'timestamp':
+      return varintField(field, (value as Date).getTime());
+    case 'json':
+      return stringField(field, JSON.stringify(value));
+  }
+};
+
+/**
+ * Encode a room as a Room message. Columns the query did not select, and nulls, are left out,
+ * which protobuf readers see as unset.
+ */
+export const encodeRoom = (room: Partial<Room>): Buffer =>
+  Buffer.concat(ROOM_FIELDS
+    .filter(([column]) => room[column] !== undefined && room[column] !== null)
+    .map(([column, field, kind]) => encodeField(field, kind, room[column])));
+
+// Encode a page of the room list as a RoomList message
+export const encodeRoomList = (
+  rooms: Partial<Room>[],
+  extras: { missing?: string[]; nextCursor?: string | null } = {},
+): Buffer => Buffer.concat([
+  ...rooms.map(room => bytesField(1, encodeRoom(room))),
+  ...(extras.missing || []).map(id => stringField(2, id)),
+  ...(extras.nextCursor ? [stringField(3, extras.nextCursor)] : []),
+]);
@@ -0,0 +1,10 @@
+// Why a scheduled room can't be joined right now, or undefined when it can
+export const unavailableReason = (room: { availableFrom: Date | null; availableUntil: Date | null }, now = new Date()): string | undefined => {
+  if (room.availableFrom && now < room.availableFrom) {
+    return `Room is not open yet; it opens at ${room.availableFrom.toISOString()}`;
+  }
+  if (room.availableUntil && now > room.availableUntil) {
+    return `Room closed at ${room.availableUntil.toISOString()}`;
+  }
+  return undefined;
+};
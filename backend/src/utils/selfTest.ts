@@ -0,0 +1,77 @@
+import prisma from '../lib/prisma';
+import { logger } from './logger';
+
+const expectStatus = async (response: Response, expected: number, step: string): Promise<any> => {
+  if (response.status !== expected) {
+    const body = await response.text().catch(() => '');
+    throw new Error(`Self-test step "${step}" returned ${response.status}, expected ${expected}: ${body}`);
+  }
+  return response.status === 204 ? null : response.json();
+};
+
+// Stand in for the TLS-terminating proxy. 'trust proxy' trusts the first hop, which for these
+// loopback requests is the self-test itself, so FORCE_HTTPS lets them through.
+const PROXY_HEADERS = { 'X-Forwarded-Proto': 'https' };
+
+interface SelfTestOptions {
+  // READ_ONLY refuses the create and delete steps, so only the reads are checked
+  readOnly?: boolean;
+}
+
+/**
+ * Exercise the room handlers end to end against the real database:
+ * create a temporary room, list it, fetch it and delete it.
+ * The temporary room is removed even if a step fails.
+ */
+export const runSelfTest = async (baseUrl: string, { readOnly = false }: SelfTestOptions = {}): Promise<void> => {
+  if (readOnly) {
+    logger.warn('READ_ONLY is set; the startup self-test skips creating and deleting a room', { baseUrl });
+    const listed = await expectStatus(await fetch(`${baseUrl}/rooms`, { headers: PROXY_HEADERS }), 200, 'list rooms');
+    if (!Array.isArray(listed?.data?.rooms)) {
+      throw new Error('Self-test step "list rooms" did not return a room list');
+    }
+    logger.info('Startup self-test passed (read-only)');
+    return;
+  }
+
+  const name = `self-test-${Date.now()}`;
+  let roomId: string | undefined;
+
+  logger.info('Running startup self-test', { baseUrl, name });
+
+  try {
+    const created = await expectStatus(
+      await fetch(`${baseUrl}/rooms`, {
+        method: 'POST',
+        headers: { ...PROXY_HEADERS, 'Content-Type': 'application/json' },
+        body: JSON.stringify({ name, password: 'self-test-password', expiryDays: 1 }),
+      }),
+      201,
+      'create room'
+    );
+    roomId = created?.data?.room?.id;
+    if (!roomId) {
+      throw new Error('Self-test step "create room" did not return a room id');
+    }
+
+    const listed = await expectStatus(await fetch(`${baseUrl}/rooms`, { headers: PROXY_HEADERS }), 200, 'list rooms');
+    if (!listed?.data?.rooms?.some((room: { id: string }) => room.id === roomId)) {
+      throw new Error('Self-test step "list rooms" did not include the created room');
+    }
+
+    const fetched = await expectStatus(await fetch(`${baseUrl}/rooms/${roomId}`, { headers: PROXY_HEADERS }), 200, 'get room');
+    if (fetched?.id !== roomId) {
+      throw new Error('Self-test step "get room" returned a different room');
+    }
+
+    await expectStatus(await fetch(`${baseUrl}/rooms/${roomId}`, { method: 'DELETE', headers: PROXY_HEADERS }), 200, 'delete room');
+    roomId = undefined;
+
+    logger.info('Startup self-test passed');
+  } finally {
+    if (roomId) {
+      await prisma.room.deleteMany({ where: { id: roomId } })
+        .catch(error => logger.error('Failed to clean up self-test room:', error));
+    }
+  }
+};
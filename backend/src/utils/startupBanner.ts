@@ -0,0 +1,67 @@
+import prisma from '../lib/prisma';
+import { corsConfig } from '../config/corsConfig';
+import { rateLimitConfig } from '../config/rateLimitConfig';
+import { secrets } from '../config/secrets';
+import { serverConfig } from '../config/serverConfig';
+import { telegramConfig } from '../config/telegramConfig';
+import { backupConfig } from '../config/backupConfig';
+import { logger } from './logger';
+
+// Driver, host and database name only; user and password never leave the URL
+const describeDatabase = (): string => {
+  const url = process.env.DATABASE_URL;
+  if (!url) {
+    return 'not configured';
+  }
+  try {
+    const parsed = new URL(url);
+    return `${parsed.protocol.replace(/:$/, '')} ${parsed.host}${parsed.pathname}`;
+  } catch (error) {
+    return 'unparseable DATABASE_URL';
+  }
+};
+
+// Admin seeding happens through first-time passkey setup, so report whether it has been done
+const describeAdminSeed = async (): Promise<string> => {
+  try {
+    const passkeys = await prisma.webAuthnCredential.count({ where: { userId: 'admin' } });
+    return passkeys > 0 ? `seeded (${passkeys} passkey${passkeys === 1 ? '' : 's'})` : 'not seeded: first-time setup is open';
+  } catch (error) {
+    return 'unknown: database unavailable';
+  }
+};
+
+/**
+ * Log one line summarizing which optional features this instance runs with,
+ * so "why isn't feature Y working" can be answered from the boot log.
+ * Secrets are reported as set/unset only.
+ */
+export const logStartupBanner = async (features: { oidc: boolean }): Promise<void> => {
+  const basePath = process.env.BASE_PATH || '/api';
+
+  logger.info('Startup configuration', {
+    port: serverConfig.port,
+    tls: 'off (plain HTTP, terminate TLS at the reverse proxy)',
+    forceHttps: serverConfig.forceHttps ? serverConfig.forceHttpsMode : 'off',
+    readOnly: serverConfig.readOnly,
+    cors: {
+      frontendUrl: process.env.FRONTEND_URL || null,
+      maxAge: corsConfig.maxAge,
+    },
+    rateLimiting: {
+      keyStrategy: rateLimitConfig.keyStrategy,
+      ipMax: rateLimitConfig.ipMax,
+      userMax: rateLimitConfig.userMax,
+      loginBypassCidrs: rateLimitConfig.loginBypassCidrs.length,
+    },
+    metrics: `health only (${basePath}/health/detailed)`,
+    database: describeDatabase(),
+    adminSeed: await describeAdminSeed(),
+    oidc: features.oidc,
+    telegram: telegramConfig.enabled,
+    backupScheduler: backupConfig.intervalMinutes > 0,
+    selfTest: serverConfig.selfTest,
+    secretsProvider: secrets.name,
+    adminAuthSecret: secrets.get('ADMIN_AUTH_SECRET') ? 'set' : 'missing',
+  });
+};
@@ -0,0 +1,61 @@
+import request from 'supertest';
+import express from 'express';
+import { createAccessLog } from '../src/middleware/accessLog';
+import { logger } from '../src/utils/logger';
+
+jest.mock('../src/utils/logger', () => ({
+  logger: { info: jest.fn(), warn: jest.fn(), error: jest.fn(), debug: jest.fn() },
+}));
+
+const buildApp = () => {
+  const app = express();
+  app.use(createAccessLog({ enabled: true, excludePaths: ['/healthz', '/api/health'] }));
+  app.all('*', (_req, res) => {
+    res.json({ status: 'success' });
+  });
+  return app;
+};
+
+describe('access log', () => {
+  beforeEach(() => {
+    (logger.info as jest.Mock).mockClear();
+  });
+
+  it('logs regular requests', async () => {
+    await request(buildApp()).get('/api/rooms?limit=5');
+
+    expect(logger.info).toHaveBeenCalledWith('HTTP request', expect.objectContaining({
+      method: 'GET',
+      path: '/api/rooms?limit=5',
+      status: 200,
+    }));
+  });
+
+  it('redacts share-link tokens from the path and query', async () => {
+    const app = buildApp();
+    await request(app).get('/api/join/eyJhbGciOi.payload.sig?ref=email');
+    await request(app).get('/api/rooms/validate/room-1?lang=en&token=secret-token');
+
+    expect(logger.info).toHaveBeenNthCalledWith(1, 'HTTP request', expect.objectContaining({
+      path: '/api/join/[redacted]?ref=email',
+    }));
+    expect(logger.info).toHaveBeenNthCalledWith(2, 'HTTP request', expect.objectContaining({
+      path: '/api/rooms/validate/room-1?lang=en&token=[redacted]',
+    }));
+  });
+
+  it('skips excluded paths and their sub-paths', async () => {
+    const app = buildApp();
+    await request(app).get('/healthz');
+    await request(app).get('/api/health');
+    await request(app).get('/api/health/detailed');
+
+    expect(logger.info).not.toHaveBeenCalled();
+  });
+
+  it('does not treat a shared prefix as a sub-path', async () => {
+    await request(buildApp()).get('/api/healthcheck');
+
+    expect(logger.info).toHaveBeenCalledTimes(1);
+  });
+});
@@ -0,0 +1,21 @@
+import { isAllowedOrigin } from '../src/utils/allowedOrigin';
+
+const allowed = ['https://live.example.com', 'http://localhost:8000'];
+
+describe('isAllowedOrigin', () => {
+  it('allows origins on the allowlist', () => {
+    expect(isAllowedOrigin('https://live.example.com', allowed)).toBe(true);
+    expect(isAllowedOrigin('http://localhost:8000', allowed)).toBe(true);
+  });
+
+  it('rejects origins that are not on the allowlist', () => {
+    expect(isAllowedOrigin('https://evil.example.net', allowed)).toBe(false);
+    expect(isAllowedOrigin('http://live.example.com', allowed)).toBe(false);
+    expect(isAllowedOrigin('http://localhost:3000', allowed)).toBe(false);
+    expect(isAllowedOrigin('null', allowed)).toBe(false);
+  });
+
+  it('allows requests without an Origin header', () => {
+    expect(isAllowedOrigin(undefined, allowed)).toBe(true);
+  });
+});
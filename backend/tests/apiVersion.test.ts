@@ -0,0 +1,44 @@
+import request from 'supertest';
+import express from 'express';
+
+jest.mock('@prisma/client', () => {
+  const room = { count: jest.fn().mockResolvedValue(0), findMany: jest.fn().mockResolvedValue([]) };
+  const client: any = { $use: jest.fn(), $connect: jest.fn().mockResolvedValue(undefined), room };
+  return { PrismaClient: jest.fn(() => client), Prisma: { DbNull: 'DbNull' } };
+});
+
+import { apiVersion } from '../src/middleware/apiVersion';
+import roomRoutes from '../src/routes/rooms';
+
+const app = express();
+app.use(apiVersion);
+app.use('/api/rooms', roomRoutes);
+app.get('/api/limited', (_req, res) => {
+  res.status(429).send('Too many requests, please try again later.');
+});
+
+const V1 = 'application/vnd.colourstream.v1+json';
+
+describe('API version negotiation', () => {
+  it('labels the room list with the negotiated vendor media type', async () => {
+    const res = await request(app).get('/api/rooms').set('Accept', V1);
+
+    expect(res.status).toBe(200);
+    expect(res.headers['content-type']).toMatch(/^application\/vnd\.colourstream\.v1\+json/);
+    expect(res.headers.vary).toMatch(/Accept/);
+  });
+
+  it('leaves other responses with their own content type', async () => {
+    const res = await request(app).get('/api/limited').set('Accept', V1);
+
+    expect(res.status).toBe(429);
+    expect(res.headers['content-type']).toMatch(/^text\/html/);
+  });
+
+  it('refuses an unsupported version with 406', async () => {
+    const res = await request(app).get('/api/rooms').set('Accept', 'application/vnd.colourstream.v9+json');
+
+    expect(res.status).toBe(406);
+    expect(res.body.message).toBe('Unsupported API version v9; supported versions: v1');
+  });
+});
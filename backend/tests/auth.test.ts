@@ -5,6 +5,7 @@ import jwt from 'jsonwebtoken';
 // Mock PrismaClient before it's used in uploadRoutes to prevent DB connection errors.
 jest.mock('@prisma/client', () => ({
   PrismaClient: jest.fn(() => ({
+    $use: jest.fn(),
     client: {
       findMany: jest.fn().mockRejectedValue(new Error('Simulated DB Error')),
     },
@@ -0,0 +1,58 @@
+import { BoundedCache } from '../src/utils/boundedCache';
+
+describe('BoundedCache', () => {
+  let now: number;
+  const clock = () => now;
+
+  beforeEach(() => {
+    now = 0;
+  });
+
+  it('evicts the least recently used entry once full', () => {
+    const cache = new BoundedCache<string, number>({ maxEntries: 2, ttlMs: 1000, sweepIntervalMs: 0, now: clock });
+
+    cache.set('a', 1);
+    cache.set('b', 2);
+    cache.get('a'); // a is now more recent than b
+    cache.set('c', 3);
+
+    expect(cache.get('a')).toBe(1);
+    expect(cache.has('b')).toBe(false);
+    expect(cache.get('c')).toBe(3);
+  });
+
+  it('expires entries after the TTL', () => {
+    const cache = new BoundedCache<string, number>({ maxEntries: 10, ttlMs: 1000, sweepIntervalMs: 0, now: clock });
+
+    cache.set('upload-1', 50);
+    now = 999;
+    expect(cache.get('upload-1')).toBe(50);
+    now = 1000;
+    expect(cache.get('upload-1')).toBeUndefined();
+  });
+
+  it('sweeps expired entries that are never read again', () => {
+    const cache = new BoundedCache<string, number>({ maxEntries: 10, ttlMs: 1000, sweepIntervalMs: 0, now: clock });
+
+    cache.set('old', 1);
+    now = 500;
+    cache.set('new', 2);
+    now = 1200;
+
+    expect(cache.sweep()).toBe(1);
+    expect(cache.size).toBe(1);
+    expect(cache.get('new')).toBe(2);
+  });
+
+  it('stays bounded under a long stream of distinct keys', () => {
+    const cache = new BoundedCache<string, number>({ maxEntries: 100, ttlMs: 60000, sweepIntervalMs: 0, now: clock });
+
+    for (let i = 0; i < 10000; i++) {
+      cache.set(`upload-${i}`, i);
+    }
+
+    expect(cache.size).toBe(100);
+    expect(cache.has('upload-0')).toBe(false);
+    expect(cache.get('upload-9999')).toBe(9999);
+  });
+});
@@ -0,0 +1,60 @@
+jest.mock('../src/utils/logger', () => ({ logger: { warn: jest.fn(), info: jest.fn() } }));
+
+import { dbCircuitBreaker } from '../src/utils/circuitBreaker';
+
+describe('Database circuit breaker', () => {
+  let now = 1_000_000;
+
+  beforeAll(() => {
+    jest.spyOn(Date, 'now').mockImplementation(() => now);
+  });
+
+  afterAll(() => {
+    jest.restoreAllMocks();
+  });
+
+  const openBreaker = () => {
+    dbCircuitBreaker.recordSuccess();
+    for (let i = 0; i < 5; i++) {
+      dbCircuitBreaker.recordFailure();
+    }
+    expect(dbCircuitBreaker.getStatus().state).toBe('open');
+  };
+
+  it('lets exactly one probe through once the cooldown has elapsed', () => {
+    openBreaker();
+    expect(dbCircuitBreaker.allowRequest()).toBe(false);
+
+    now += 30_000;
+    expect(dbCircuitBreaker.allowRequest()).toBe(true);
+    expect(dbCircuitBreaker.getStatus().state).toBe('half_open');
+    expect(dbCircuitBreaker.allowRequest()).toBe(false);
+    expect(dbCircuitBreaker.allowRequest()).toBe(false);
+
+    dbCircuitBreaker.recordSuccess();
+    expect(dbCircuitBreaker.getStatus().state).toBe('closed');
+    expect(dbCircuitBreaker.allowRequest()).toBe(true);
+  });
+
+  it('reopens when the probe fails', () => {
+    openBreaker();
+    now += 30_000;
+    expect(dbCircuitBreaker.allowRequest()).toBe(true);
+
+    dbCircuitBreaker.recordFailure();
+    expect(dbCircuitBreaker.getStatus().state).toBe('open');
+    expect(dbCircuitBreaker.allowRequest()).toBe(false);
+  });
+
+  it('gives up on a probe that never settles after another cooldown', () => {
+    openBreaker();
+    now += 30_000;
+    expect(dbCircuitBreaker.allowRequest()).toBe(true);
+
+    now += 29_000;
+    expect(dbCircuitBreaker.allowRequest()).toBe(false);
+    now += 1_000;
+    expect(dbCircuitBreaker.allowRequest()).toBe(true);
+    expect(dbCircuitBreaker.allowRequest()).toBe(false);
+  });
+});
@@ -0,0 +1,56 @@
+import { envDurationMs, envPositiveInt } from '../src/utils/env';
+
+describe('env helpers', () => {
+  const NAME = 'ENV_HELPER_TEST';
+  let warn: jest.SpyInstance;
+
+  beforeEach(() => {
+    warn = jest.spyOn(console, 'warn').mockImplementation(() => undefined);
+  });
+
+  afterEach(() => {
+    delete process.env[NAME];
+    warn.mockRestore();
+  });
+
+  describe('envPositiveInt', () => {
+    it('reads a positive integer and falls back when unset or blank', () => {
+      expect(envPositiveInt(NAME, 5)).toBe(5);
+      process.env[NAME] = ' ';
+      expect(envPositiveInt(NAME, 5)).toBe(5);
+      process.env[NAME] = ' 42 ';
+      expect(envPositiveInt(NAME, 5)).toBe(42);
+      expect(warn).not.toHaveBeenCalled();
+    });
+
+    it.each(['0', '-3', '1.5', 'ten'])('warns and falls back on %p', (value) => {
+      process.env[NAME] = value;
+      expect(envPositiveInt(NAME, 5)).toBe(5);
+      expect(warn).toHaveBeenCalledWith(`Invalid ${NAME} "${value}", falling back to 5.`);
+    });
+
+    it('accepts 0 with allowZero', () => {
+      process.env[NAME] = '0';
+      expect(envPositiveInt(NAME, 5, { allowZero: true })).toBe(0);
+    });
+  });
+
+  describe('envDurationMs', () => {
+    it('reads a duration and falls back when unset', () => {
+      expect(envDurationMs(NAME, '30s')).toBe(30_000);
+      process.env[NAME] = '1h30m';
+      expect(envDurationMs(NAME, '30s')).toBe(5_400_000);
+    });
+
+    it.each(['0', '0s', '10', 'soon'])('warns and falls back on %p', (value) => {
+      process.env[NAME] = value;
+      expect(envDurationMs(NAME, '30s')).toBe(30_000);
+      expect(warn).toHaveBeenCalledWith(`Invalid ${NAME} "${value}", falling back to 30s.`);
+    });
+
+    it('accepts a bare 0 with allowZero', () => {
+      process.env[NAME] = '0';
+      expect(envDurationMs(NAME, '30s', { allowZero: true })).toBe(0);
+    });
+  });
+});
@@ -0,0 +1,142 @@
+import request from 'supertest';
+import express from 'express';
+import { assignRequestId } from '../src/middleware/requestId';
+import { AppError, errorHandler } from '../src/middleware/errorHandler';
+import { logger } from '../src/utils/logger';
+import { asyncRouter } from '../src/utils/asyncRouter';
+
+let app: express.Application;
+
+beforeAll(() => {
+  app = express();
+  app.use(assignRequestId);
+  app.get('/panic', () => {
+    const settings: any = undefined;
+    settings.codec.toString(); // Deliberate TypeError, like a nil map access
+  });
+  app.get('/not-found', () => {
+    throw new AppError(404, 'Room not found');
+  });
+  const router = asyncRouter();
+  router.get('/async-panic', async () => {
+    await Promise.resolve();
+    throw new TypeError('Cannot read properties of undefined');
+  });
+  app.use(router);
+  app.use(errorHandler);
+});
+
+afterEach(() => {
+  jest.restoreAllMocks();
+});
+
+describe('Error recovery', () => {
+  it('turns a throwing handler into a JSON 500 and logs the stack with the request id', async () => {
+    const logSpy = jest.spyOn(logger, 'error').mockImplementation(() => logger);
+
+    const res = await request(app).get('/panic');
+
+    expect(res.status).toBe(500);
+    expect(res.body).toEqual({ status: 'error', message: 'Internal server error' });
+
+    const requestId = res.headers['x-request-id'];
+    expect(requestId).toBeTruthy();
+    expect(logSpy).toHaveBeenCalledWith('Error:', expect.objectContaining({
+      name: 'TypeError',
+      requestId,
+      stack: expect.any(String),
+    }));
+  });
+
+  it('answers a rejected async handler with a JSON 500 instead of hanging', async () => {
+    const logSpy = jest.spyOn(logger, 'error').mockImplementation(() => logger);
+
+    const res = await request(app).get('/async-panic');
+
+    expect(res.status).toBe(500);
+    expect(res.body).toEqual({ status: 'error', message: 'Internal server error' });
+    expect(logSpy).toHaveBeenCalledWith('Error:', expect.objectContaining({
+      name: 'TypeError',
+      requestId: res.headers['x-request-id'],
+    }));
+  });
+
+  it('keeps serving requests after a handler throws', async () => {
+    jest.spyOn(logger, 'error').mockImplementation(() => logger);
+
+    await request(app).get('/panic');
+    const res = await request(app).get('/not-found');
+
+    expect(res.status).toBe(404);
+    expect(res.body).toEqual({ status: 'error', message: 'Room not found' });
+  });
+
+  it('renders an HTML error page for browsers with the same status code', async () => {
+    const res = await request(app)
+      .get('/not-found')
+      .set('Accept', 'text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8');
+
+    expect(res.status).toBe(404);
+    expect(res.headers['content-type']).toMatch(/text\/html/);
+    expect(res.text).toContain('Room not found');
+  });
+
+  it('keeps JSON for API clients', async () => {
+    const res = await request(app).get('/not-found').set('Accept', 'application/json');
+
+    expect(res.status).toBe(404);
+    expect(res.body).toEqual({ status: 'error', message: 'Room not found' });
+  });
+
+  it('reuses a well-formed X-Request-ID from the caller', async () => {
+    const res = await request(app).get('/not-found').set('X-Request-ID', 'proxy-abc-123');
+    expect(res.headers['x-request-id']).toBe('proxy-abc-123');
+  });
+
+  it('replaces a malformed X-Request-ID', async () => {
+    const res = await request(app).get('/not-found').set('X-Request-ID', 'bad id; with spaces');
+    expect(res.headers['x-request-id']).not.toBe('bad id; with spaces');
+  });
+});
+
+describe('JSON request bodies', () => {
+  let bodyApp: express.Application;
+
+  beforeAll(() => {
+    bodyApp = express();
+    bodyApp.use(express.json());
+    bodyApp.post('/rooms', (req, res) => {
+      const { name } = req.body;
+      res.status(201).json({ status: 'success', data: { name: name || 'room-generated' } });
+    });
+    bodyApp.use(errorHandler);
+  });
+
+  beforeEach(() => {
+    jest.spyOn(logger, 'error').mockImplementation(() => logger);
+  });
+
+  it('treats an empty body as no fields provided', async () => {
+    const res = await request(bodyApp).post('/rooms').set('Content-Type', 'application/json');
+
+    expect(res.status).toBe(201);
+    expect(res.body.data.name).toBe('room-generated');
+  });
+
+  it('reads fields from a valid body', async () => {
+    const res = await request(bodyApp).post('/rooms').send({ name: 'studio-a' });
+
+    expect(res.status).toBe(201);
+    expect(res.body.data.name).toBe('studio-a');
+  });
+
+  it('rejects a malformed body with a 400 naming the problem', async () => {
+    const res = await request(bodyApp)
+      .post('/rooms')
+      .set('Content-Type', 'application/json')
+      .send('{"name": "studio-a"');
+
+    expect(res.status).toBe(400);
+    expect(res.body).toEqual({ status: 'error', message: 'Malformed JSON in request body' });
+  });
+});
@@ -0,0 +1,49 @@
+import request from 'supertest';
+import express from 'express';
+import { createForceHttps } from '../src/middleware/forceHttps';
+
+const buildApp = (mode: 'redirect' | 'reject') => {
+  const app = express();
+  app.set('trust proxy', 1);
+  app.use(createForceHttps({ enabled: true, mode }));
+  app.all('/api/rooms', (_req, res) => {
+    res.json({ status: 'success' });
+  });
+  return app;
+};
+
+describe('FORCE_HTTPS', () => {
+  it('passes requests forwarded over https', async () => {
+    for (const mode of ['redirect', 'reject'] as const) {
+      const res = await request(buildApp(mode)).get('/api/rooms').set('X-Forwarded-Proto', 'https');
+      expect(res.status).toBe(200);
+    }
+  });
+
+  it('redirects plain http to the https URL with 308', async () => {
+    const res = await request(buildApp('redirect'))
+      .post('/api/rooms?limit=5')
+      .set('Host', 'live.example.com')
+      .set('X-Forwarded-Proto', 'http');
+
+    expect(res.status).toBe(308);
+    expect(res.headers.location).toBe('https://live.example.com/api/rooms?limit=5');
+  });
+
+  it('rejects plain http with 400 in reject mode', async () => {
+    const res = await request(buildApp('reject')).get('/api/rooms').set('X-Forwarded-Proto', 'http');
+
+    expect(res.status).toBe(400);
+    expect(res.body).toEqual({ status: 'error', message: 'HTTPS is required' });
+  });
+
+  it('leaves requests alone when disabled', async () => {
+    const app = express();
+    app.use(createForceHttps({ enabled: false, mode: 'reject' }));
+    app.get('/api/rooms', (_req, res) => {
+      res.json({ status: 'success' });
+    });
+
+    expect((await request(app).get('/api/rooms').set('X-Forwarded-Proto', 'http')).status).toBe(200);
+  });
+});
@@ -0,0 +1,28 @@
+import { applyMergePatch } from '../src/utils/mergePatch';
+
+describe('applyMergePatch', () => {
+  it('leaves omitted members unchanged and replaces given ones', () => {
+    expect(applyMergePatch({ name: 'a', settings: { bitrate: 1 } }, { name: 'b' }))
+      .toEqual({ name: 'b', settings: { bitrate: 1 } });
+  });
+
+  it('removes members set to null', () => {
+    expect(applyMergePatch({ name: 'a', settings: { bitrate: 1 } }, { settings: null })).toEqual({ name: 'a' });
+  });
+
+  it('merges nested objects recursively', () => {
+    expect(applyMergePatch(
+      { settings: { bitrate: 1, codec: 'h264', audio: { channels: 2 } } },
+      { settings: { codec: null, audio: { rate: 48000 } } }
+    )).toEqual({ settings: { bitrate: 1, audio: { channels: 2, rate: 48000 } } });
+  });
+
+  it('replaces arrays and non-object targets wholesale', () => {
+    expect(applyMergePatch({ tags: ['a', 'b'] }, { tags: ['c'] })).toEqual({ tags: ['c'] });
+    expect(applyMergePatch({ settings: 'x' }, { settings: { bitrate: 1 } })).toEqual({ settings: { bitrate: 1 } });
+  });
+
+  it('treats a non-object patch as a replacement document', () => {
+    expect(applyMergePatch({ name: 'a' }, ['x'])).toEqual(['x']);
+  });
+});
@@ -0,0 +1,42 @@
+import request from 'supertest';
+import express from 'express';
+import cors from 'cors';
+import { discoverableOptions, unlessDiscovery } from '../src/middleware/optionsAllow';
+
+let app: express.Application;
+
+beforeAll(() => {
+  const rooms = express.Router();
+  rooms.get('/:id', (_req, res) => {
+    res.json({ status: 'success' });
+  });
+  rooms.delete('/:id', (_req, res) => {
+    res.json({ status: 'success' });
+  });
+
+  app = express();
+  app.use(discoverableOptions);
+  app.use(unlessDiscovery(cors({ origin: true })));
+  app.use('/api/rooms', rooms);
+});
+
+describe('OPTIONS discoverability', () => {
+  it('answers a bare OPTIONS with 204 and the methods the route supports', async () => {
+    const res = await request(app).options('/api/rooms/abc');
+
+    expect(res.status).toBe(204);
+    expect(res.headers.allow).toBe('GET,HEAD,DELETE');
+    expect(res.text).toBe('');
+  });
+
+  it('leaves CORS preflights to the cors middleware', async () => {
+    const res = await request(app)
+      .options('/api/rooms/abc')
+      .set('Origin', 'https://live.example.com')
+      .set('Access-Control-Request-Method', 'DELETE');
+
+    expect(res.status).toBe(204);
+    expect(res.headers['access-control-allow-methods']).toBeDefined();
+    expect(res.headers.allow).toBeUndefined();
+  });
+});
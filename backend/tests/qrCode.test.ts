@@ -0,0 +1,96 @@
+import request from 'supertest';
+import express from 'express';
+import { PrismaClient } from '@prisma/client';
+
+jest.mock('@prisma/client', () => {
+  const client: any = { $use: jest.fn(), $connect: jest.fn().mockResolvedValue(undefined), room: { findUnique: jest.fn() } };
+  return { PrismaClient: jest.fn(() => client), Prisma: { DbNull: 'DbNull' } };
+});
+
+import roomRoutes from '../src/routes/rooms';
+import { encodeQrCode, formatBits, reedSolomonDivisor, reedSolomonRemainder } from '../src/utils/qrCode';
+
+const db = new (PrismaClient as any)();
+
+const app = express();
+app.use('/api/rooms', roomRoutes);
+
+const FINDER = [
+  [1, 1, 1, 1, 1, 1, 1],
+  [1, 0, 0, 0, 0, 0, 1],
+  [1, 0, 1, 1, 1, 0, 1],
+  [1, 0, 1, 1, 1, 0, 1],
+  [1, 0, 1, 1, 1, 0, 1],
+  [1, 0, 0, 0, 0, 0, 1],
+  [1, 1, 1, 1, 1, 1, 1],
+];
+
+const finderAt = (modules: boolean[][], left: number, top: number): number[][] =>
+  FINDER.map((row, y) => row.map((_, x) => (modules[top + y][left + x] ? 1 : 0)));
+
+describe('QR code encoder', () => {
+  it('computes Reed-Solomon error correction for a version 1-M block', () => {
+    // "HELLO WORLD" at version 1-M, from the ISO/IEC 18004 worked example
+    const data = [32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17];
+
+    expect(reedSolomonRemainder(data, reedSolomonDivisor(10))).toEqual([196, 35, 39, 119, 235, 215, 231, 226, 93, 23]);
+  });
+
+  it('encodes level M format information with its BCH code and mask', () => {
+    expect(formatBits(0).toString(2).padStart(15, '0')).toBe('101010000010010');
+  });
+
+  it('picks the smallest version that fits and draws the finder patterns', () => {
+    const small = encodeQrCode('https://example.com/r/1');
+    const large = encodeQrCode(`https://example.com/room/${'a'.repeat(150)}`);
+
+    expect(small).toHaveLength(25);
+    expect(large).toHaveLength(53);
+    for (const modules of [small, large]) {
+      const end = modules.length - 7;
+      expect(finderAt(modules, 0, 0)).toEqual(FINDER);
+      expect(finderAt(modules, end, 0)).toEqual(FINDER);
+      expect(finderAt(modules, 0, end)).toEqual(FINDER);
+    }
+  });
+
+  it('rejects text longer than version 10 holds', () => {
+    expect(() => encodeQrCode('a'.repeat(214))).toThrow('Text too long');
+  });
+});
+
+describe('GET /rooms/:id/qrcode', () => {
+  beforeEach(() => {
+    db.room.findUnique.mockImplementation(async ({ where }: any) =>
+      where.id === 'room-1' ? { id: 'room-1', mirotalkRoomId: 'mt-1', link: 'https://live.example.com/room/room-1' } : null);
+  });
+
+  it('returns a PNG of the requested size by default', async () => {
+    const res = await request(app).get('/api/rooms/room-1/qrcode?size=128').buffer(true);
+
+    expect(res.status).toBe(200);
+    expect(res.headers['content-type']).toBe('image/png');
+    expect(res.body.subarray(1, 4).toString('ascii')).toBe('PNG');
+    expect(res.body.readUInt32BE(16)).toBe(128);
+    expect(res.body.readUInt32BE(20)).toBe(128);
+  });
+
+  it('returns an SVG when asked', async () => {
+    const res = await request(app).get('/api/rooms/room-1/qrcode?format=svg');
+
+    expect(res.status).toBe(200);
+    expect(res.headers['content-type']).toMatch(/^image\/svg\+xml/);
+    expect(res.text).toContain('width="256" height="256"');
+  });
+
+  it.each(['10', '2048'])('rejects an out-of-range size of %s', async (size) => {
+    const res = await request(app).get(`/api/rooms/room-1/qrcode?size=${size}`);
+
+    expect(res.status).toBe(400);
+    expect(res.body.error).toMatch(/^Invalid size/);
+  });
+
+  it('returns 404 for an unknown room', async () => {
+    expect((await request(app).get('/api/rooms/missing/qrcode')).status).toBe(404);
+  });
+});
@@ -0,0 +1,82 @@
+import { Request } from 'express';
+import { queryDuration, queryInt } from '../src/utils/queryParams';
+import { AppError } from '../src/middleware/errorHandler';
+
+const withQuery = (query: Record<string, unknown>) => ({ query } as unknown as Request);
+
+const rejectionOf = (fn: () => number): AppError | undefined => {
+  try {
+    fn();
+    return undefined;
+  } catch (error) {
+    return error as AppError;
+  }
+};
+
+describe('queryInt', () => {
+  it('returns the default when the parameter is absent', () => {
+    expect(queryInt(withQuery({}), 'limit', 10, { min: 1, max: 100 })).toBe(10);
+  });
+
+  it('parses an in-range integer', () => {
+    expect(queryInt(withQuery({ limit: '25' }), 'limit', 10, { min: 1, max: 100 })).toBe(25);
+  });
+
+  it('rejects malformed values with a 400 naming the field and range', () => {
+    for (const limit of ['abc', '1.5', '', '10abc', ['1', '2']]) {
+      const error = rejectionOf(() => queryInt(withQuery({ limit }), 'limit', 10, { min: 1, max: 100 }));
+      expect(error).toBeInstanceOf(AppError);
+      expect(error?.statusCode).toBe(400);
+      expect(error?.message).toBe('Invalid limit: expected an integer between 1 and 100');
+    }
+  });
+
+  it('rejects out-of-range values unless clamping', () => {
+    const error = rejectionOf(() => queryInt(withQuery({ limit: '100000' }), 'limit', 10, { min: 1, max: 100 }));
+    expect(error?.statusCode).toBe(400);
+
+    expect(queryInt(withQuery({ limit: '100000' }), 'limit', 10, { min: 1, max: 100, clamp: true })).toBe(100);
+    expect(queryInt(withQuery({ limit: '0' }), 'limit', 10, { min: 1, max: 100, clamp: true })).toBe(1);
+  });
+
+  it('still rejects malformed values when clamping', () => {
+    const error = rejectionOf(() => queryInt(withQuery({ page: 'two' }), 'page', 1, { min: 1, clamp: true }));
+    expect(error?.message).toBe('Invalid page: expected an integer of at least 1');
+  });
+});
+
+describe('queryDuration', () => {
+  const rejectionOfDuration = (query: Record<string, unknown>, options = { min: 60_000, max: 86_400_000 }) =>
+    rejectionOf(() => queryDuration(withQuery(query), 'window', 3_600_000, options));
+
+  it('returns the default when the parameter is absent', () => {
+    expect(queryDuration(withQuery({}), 'window', 3_600_000)).toBe(3_600_000);
+  });
+
+  it('parses a duration into milliseconds', () => {
+    expect(queryDuration(withQuery({ window: '1h30m' }), 'window', 0)).toBe(5_400_000);
+  });
+
+  it('rejects malformed values with a 400 naming the field and range', () => {
+    for (const window of ['soon', '10', '', '-5m', ['1h', '2h']]) {
+      const error = rejectionOfDuration({ window });
+      expect(error).toBeInstanceOf(AppError);
+      expect(error?.statusCode).toBe(400);
+      expect(error?.message).toBe('Invalid window: expected a duration between 1m and 1d');
+    }
+  });
+
+  it('gives an example when there is no range', () => {
+    const error = rejectionOf(() => queryDuration(withQuery({ window: 'soon' }), 'window', 0));
+    expect(error?.message).toBe('Invalid window: expected a duration such as 30s or 2h');
+  });
+
+  it('rejects out-of-range values unless clamping', () => {
+    expect(rejectionOfDuration({ window: '2d' })?.statusCode).toBe(400);
+    expect(rejectionOfDuration({ window: '30s' })?.message).toBe('Invalid window: expected a duration between 1m and 1d');
+
+    const clamped = { min: 60_000, max: 86_400_000, clamp: true };
+    expect(queryDuration(withQuery({ window: '2d' }), 'window', 0, clamped)).toBe(86_400_000);
+    expect(queryDuration(withQuery({ window: '30s' }), 'window', 0, clamped)).toBe(60_000);
+  });
+});
@@ -0,0 +1,108 @@
+import request from 'supertest';
+import express from 'express';
+import jwt from 'jsonwebtoken';
+
+// Mock PrismaClient before the security middleware pulls in the blocked-IP service.
+jest.mock('@prisma/client', () => ({
+  PrismaClient: jest.fn(() => ({
+    $use: jest.fn(),
+    $connect: jest.fn().mockResolvedValue(undefined),
+  })),
+}));
+
+import { createGeneralLimiter, createLoginLimiter, failedLoginDelayMs } from '../src/middleware/security';
+
+const tokenFor = (userId: string) => jwt.sign({ userId, type: 'admin' }, process.env.ADMIN_AUTH_SECRET!);
+
+const buildApp = (keyStrategy: 'ip' | 'user') => {
+  const app = express();
+  app.use(createGeneralLimiter({ keyStrategy, ipMax: 2, userMax: 2 }));
+  app.get('/api/rooms', (_req, res) => {
+    res.json({ status: 'success' });
+  });
+  return app;
+};
+
+beforeAll(() => {
+  process.env.ADMIN_AUTH_SECRET = 'test-secret';
+});
+
+describe('General rate limiter keying', () => {
+  it('gives users behind the same IP independent buckets with the user strategy', async () => {
+    const app = buildApp('user');
+    const alice = `Bearer ${tokenFor('alice')}`;
+    const bob = `Bearer ${tokenFor('bob')}`;
+
+    expect((await request(app).get('/api/rooms').set('Authorization', alice)).status).toBe(200);
+    expect((await request(app).get('/api/rooms').set('Authorization', alice)).status).toBe(200);
+    expect((await request(app).get('/api/rooms').set('Authorization', alice)).status).toBe(429);
+
+    // Same client IP, different user: unaffected by alice's exhausted bucket
+    expect((await request(app).get('/api/rooms').set('Authorization', bob)).status).toBe(200);
+  });
+
+  it('keeps anonymous requests per IP with the user strategy', async () => {
+    const app = buildApp('user');
+
+    expect((await request(app).get('/api/rooms')).status).toBe(200);
+    expect((await request(app).get('/api/rooms')).status).toBe(200);
+    expect((await request(app).get('/api/rooms')).status).toBe(429);
+  });
+
+  it('shares one bucket per IP across users with the ip strategy', async () => {
+    const app = buildApp('ip');
+
+    expect((await request(app).get('/api/rooms').set('Authorization', `Bearer ${tokenFor('alice')}`)).status).toBe(200);
+    expect((await request(app).get('/api/rooms').set('Authorization', `Bearer ${tokenFor('alice')}`)).status).toBe(200);
+    expect((await request(app).get('/api/rooms').set('Authorization', `Bearer ${tokenFor('bob')}`)).status).toBe(429);
+  });
+
+  it('does not let a forged token escape the IP bucket', async () => {
+    const app = buildApp('user');
+    const forged = `Bearer ${jwt.sign({ userId: 'mallory', type: 'admin' }, 'wrong-secret')}`;
+
+    expect((await request(app).get('/api/rooms').set('Authorization', forged)).status).toBe(200);
+    expect((await request(app).get('/api/rooms').set('Authorization', forged)).status).toBe(200);
+    expect((await request(app).get('/api/rooms')).status).toBe(429);
+  });
+});
+
+describe('Login rate limiter bypass', () => {
+  const buildLoginApp = (loginBypassCidrs: string[]) => {
+    const app = express();
+    app.post('/api/auth/webauthn/authenticate', createLoginLimiter({ loginBypassCidrs }), (_req, res) => {
+      res.json({ status: 'success' });
+    });
+    return app;
+  };
+
+  const attempt = (app: express.Express) => request(app).post('/api/auth/webauthn/authenticate');
+
+  it('does not limit clients inside an allowlisted range', async () => {
+    const app = buildLoginApp(['127.0.0.0/8', '::1/128']);
+
+    for (let i = 0; i < 12; i++) {
+      expect((await attempt(app)).status).toBe(200);
+    }
+  });
+
+  it('still limits clients outside the allowlist', async () => {
+    const app = buildLoginApp(['10.0.0.0/8']);
+
+    for (let i = 0; i < 10; i++) {
+      expect((await attempt(app)).status).toBe(200);
+    }
+    expect((await attempt(app)).status).toBe(429);
+  });
+});
+
+describe('Failed login delay', () => {
+  it('doubles with each failure up to the cap', () => {
+    expect([1, 2, 3, 4].map(attempts => failedLoginDelayMs(attempts, 250, 1500))).toEqual([250, 500, 1000, 1500]);
+    expect(failedLoginDelayMs(100, 250, 1500)).toBe(1500);
+  });
+
+  it('is disabled by a zero base delay', () => {
+    expect(failedLoginDelayMs(5, 0, 1500)).toBe(0);
+  });
+});
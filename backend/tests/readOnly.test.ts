@@ -0,0 +1,46 @@
+import request from 'supertest';
+import express from 'express';
+import { createReadOnly } from '../src/middleware/readOnly';
+
+const buildApp = (enabled: boolean) => {
+  const app = express();
+  app.use(createReadOnly({ enabled, basePath: '/api' }));
+  app.all('*', (_req, res) => {
+    res.json({ status: 'success' });
+  });
+  return app;
+};
+
+describe('READ_ONLY', () => {
+  it('refuses writes with a 403', async () => {
+    const app = buildApp(true);
+    for (const send of [
+      request(app).post('/api/rooms'),
+      request(app).put('/api/rooms/by-name/demo'),
+      request(app).patch('/api/rooms/abc'),
+      request(app).delete('/api/rooms/abc'),
+      request(app).post('/api/auth/webauthn/register'),
+    ]) {
+      const res = await send;
+      expect(res.status).toBe(403);
+      expect(res.body).toEqual({ status: 'error', message: 'Server is read-only' });
+    }
+  });
+
+  it('lets reads through', async () => {
+    const app = buildApp(true);
+    expect((await request(app).get('/api/rooms')).status).toBe(200);
+    expect((await request(app).head('/api/rooms')).status).toBe(200);
+  });
+
+  it('lets sign-in and room validation through', async () => {
+    const app = buildApp(true);
+    expect((await request(app).post('/api/auth/webauthn/authenticate')).status).toBe(200);
+    expect((await request(app).post('/api/auth/webauthn/authenticate/verify')).status).toBe(200);
+    expect((await request(app).post('/api/rooms/validate/abc')).status).toBe(200);
+  });
+
+  it('does nothing when disabled', async () => {
+    expect((await request(buildApp(false)).delete('/api/rooms/abc')).status).toBe(200);
+  });
+});
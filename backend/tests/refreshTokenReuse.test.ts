@@ -0,0 +1,65 @@
+import request from 'supertest';
+import express from 'express';
+import jwt from 'jsonwebtoken';
+import { createHash } from 'crypto';
+import { PrismaClient } from '@prisma/client';
+
+jest.mock('@prisma/client', () => {
+  const refreshToken = { findUnique: jest.fn(), findFirst: jest.fn(), updateMany: jest.fn(), create: jest.fn(), update: jest.fn() };
+  const client: any = { $use: jest.fn(), $connect: jest.fn().mockResolvedValue(undefined), refreshToken };
+  return { PrismaClient: jest.fn(() => client), Prisma: {} };
+});
+
+import { authenticateToken } from '../src/middleware/auth';
+import { refreshTokenService } from '../src/services/refreshTokens';
+
+const db = new (PrismaClient as any)();
+
+process.env.ADMIN_AUTH_SECRET = 'test-secret';
+
+const app = express();
+app.get('/protected', authenticateToken, (_req, res) => res.json({ status: 'success' }));
+
+const hash = (token: string) => createHash('sha256').update(token).digest('hex');
+
+describe('Refresh token reuse', () => {
+  it('rejects access tokens from the session once reuse is detected', async () => {
+    // The presented token was already rotated, so it is marked revoked
+    db.refreshToken.findUnique.mockResolvedValue({
+      id: 'rt-1',
+      tokenHash: hash('stolen-token'),
+      familyId: 'family-1',
+      userId: 'admin',
+      revokedAt: new Date(Date.now() - 1000),
+      expiresAt: new Date(Date.now() + 60_000),
+    });
+    db.refreshToken.updateMany.mockResolvedValue({ count: 1 });
+    db.refreshToken.findFirst.mockResolvedValue({ id: 'rt-2' });
+
+    const accessToken = jwt.sign({ userId: 'admin', type: 'admin', sid: 'family-1' }, 'test-secret');
+    expect((await request(app).get('/protected').set('Authorization', `Bearer ${accessToken}`)).status).toBe(200);
+
+    await expect(refreshTokenService.rotate('stolen-token')).rejects.toThrow('Refresh token has been revoked');
+    expect(db.refreshToken.updateMany).toHaveBeenCalledWith(expect.objectContaining({
+      where: { familyId: 'family-1', revokedAt: null },
+    }));
+
+    const res = await request(app).get('/protected').set('Authorization', `Bearer ${accessToken}`);
+    expect(res.status).toBe(401);
+    expect(res.body.message).toBe('Session has been revoked');
+  });
+
+  it('rejects access tokens from a session revoked by another replica', async () => {
+    // No live refresh token is left in the family, though this process never revoked it
+    db.refreshToken.findFirst.mockResolvedValue(null);
+
+    const accessToken = jwt.sign({ userId: 'admin', type: 'admin', sid: 'family-2' }, 'test-secret');
+    const res = await request(app).get('/protected').set('Authorization', `Bearer ${accessToken}`);
+
+    expect(res.status).toBe(401);
+    expect(res.body.message).toBe('Session has been revoked');
+    expect(db.refreshToken.findFirst).toHaveBeenCalledWith(expect.objectContaining({
+      where: { familyId: 'family-2', revokedAt: null },
+    }));
+  });
+});
@@ -0,0 +1,84 @@
+import request from 'supertest';
+import express from 'express';
+import { Readable } from 'stream';
+
+jest.mock('../src/utils/logger', () => ({ logger: { warn: jest.fn(), info: jest.fn() } }));
+
+import { loadServerConfig, serverConfig } from '../src/config/serverConfig';
+import { requestTimeout } from '../src/middleware/requestTimeout';
+
+const wait = (ms: number) => new Promise(resolve => setTimeout(resolve, ms));
+
+// Each handler answers only after the 20ms timeout has already sent the 503
+let lateWrite: Promise<void>;
+
+const app = express();
+app.use(requestTimeout);
+app.get('/late-json', (_req, res) => {
+  lateWrite = wait(40).then(() => {
+    res.set('X-Late', 'yes').status(200).json({ late: true });
+  });
+});
+app.get('/late-raw', (_req, res) => {
+  lateWrite = wait(40).then(() => {
+    res.setHeader('Content-Type', 'text/plain');
+    res.writeHead(200);
+    res.write('late');
+    res.end();
+  });
+});
+app.get('/late-redirect', (_req, res) => {
+  lateWrite = wait(40).then(() => {
+    res.redirect('/elsewhere');
+  });
+});
+app.get('/late-pipe', (_req, res) => {
+  lateWrite = wait(40).then(() => new Promise<void>((resolve) => {
+    const stream = Readable.from(['late ', 'stream']);
+    stream.on('end', () => resolve());
+    stream.pipe(res);
+  }));
+});
+
+describe('requestTimeout', () => {
+  const original = { requestTimeoutMs: serverConfig.requestTimeoutMs, routeTimeouts: serverConfig.routeTimeouts };
+
+  beforeAll(() => {
+    serverConfig.requestTimeoutMs = 20;
+    serverConfig.routeTimeouts = [];
+  });
+
+  afterAll(() => {
+    Object.assign(serverConfig, original);
+  });
+
+  it.each(['/late-json', '/late-raw', '/late-redirect', '/late-pipe'])('answers 503 and ignores what %s sends afterwards', async (path) => {
+    const res = await request(app).get(path);
+
+    expect(res.status).toBe(503);
+    expect(res.body.message).toBe('Request timed out after 20ms');
+    // The late write must neither throw nor alter the response that was sent
+    await expect(lateWrite).resolves.toBeUndefined();
+    expect(res.headers['x-late']).toBeUndefined();
+  });
+});
+
+describe('route timeout defaults', () => {
+  afterEach(() => {
+    delete process.env.BASE_PATH;
+  });
+
+  it('exempts the upload and backup routes under BASE_PATH', () => {
+    process.env.BASE_PATH = '/v2';
+
+    const { routeTimeouts, accessLogExcludePaths } = loadServerConfig();
+
+    expect(routeTimeouts).toEqual(expect.arrayContaining([
+      { prefix: '/v2/upload', timeoutMs: 0 },
+      { prefix: '/v2/admin/backup', timeoutMs: 0 },
+      { prefix: '/files', timeoutMs: 0 },
+    ]));
+    expect(routeTimeouts.map(({ prefix }) => prefix)).not.toContain('/api/upload');
+    expect(accessLogExcludePaths).toContain('/v2/health');
+  });
+});
@@ -0,0 +1,71 @@
+import request from 'supertest';
+import express from 'express';
+import jwt from 'jsonwebtoken';
+import { PrismaClient } from '@prisma/client';
+
+jest.mock('@prisma/client', () => {
+  class PrismaClientKnownRequestError extends Error {
+    constructor(message: string, public code: string) {
+      super(message);
+    }
+  }
+  const room = { delete: jest.fn() };
+  const client: any = { $use: jest.fn(), $connect: jest.fn().mockResolvedValue(undefined), room };
+  return { PrismaClient: jest.fn(() => client), Prisma: { DbNull: 'DbNull', PrismaClientKnownRequestError } };
+});
+
+import { Prisma } from '@prisma/client';
+import roomRoutes from '../src/routes/rooms';
+
+const db = new (PrismaClient as any)();
+
+const app = express();
+app.use(express.json());
+app.use('/api/rooms', roomRoutes);
+
+process.env.ADMIN_AUTH_SECRET = 'test-secret';
+const adminToken = jwt.sign({ userId: 'admin', type: 'admin' }, 'test-secret');
+
+const deleteRoom = (query = '') => request(app)
+  .delete(`/api/rooms/room-1${query}`)
+  .set('Authorization', `Bearer ${adminToken}`);
+
+// What Prisma throws when the row to delete is already gone, e.g. removed by a concurrent delete
+const recordNotFound = () => new (Prisma.PrismaClientKnownRequestError as any)('Record to delete does not exist.', 'P2025');
+
+describe('DELETE /rooms/:id', () => {
+  beforeEach(() => {
+    db.room.delete.mockReset();
+  });
+
+  it('returns the deleted room', async () => {
+    db.room.delete.mockResolvedValue({ id: 'room-1', mirotalkRoomId: 'mt-1', name: 'Room 1' });
+
+    const res = await deleteRoom();
+
+    expect(res.status).toBe(200);
+    expect(res.body.data).toEqual({ deleted: true, room: { id: 'room-1', mirotalkRoomId: 'mt-1', name: 'Room 1' } });
+  });
+
+  it('answers the loser of a concurrent delete with 404, or 200 when idempotent', async () => {
+    db.room.delete.mockRejectedValue(recordNotFound());
+
+    const missing = await deleteRoom();
+    expect(missing.status).toBe(404);
+    expect(missing.body).toEqual({ status: 'error', message: 'Room not found' });
+
+    const idempotent = await deleteRoom('?idempotent=true');
+    expect(idempotent.status).toBe(200);
+    expect(idempotent.body.data).toEqual({ deleted: false, room: null });
+  });
+
+  it('answers other database errors with a 500 in the error envelope', async () => {
+    jest.spyOn(console, 'error').mockImplementation(() => undefined);
+    db.room.delete.mockRejectedValue(new Error('connection reset'));
+
+    const res = await deleteRoom();
+
+    expect(res.status).toBe(500);
+    expect(res.body).toEqual({ status: 'error', message: 'Failed to delete room' });
+  });
+});
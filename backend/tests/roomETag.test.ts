@@ -0,0 +1,131 @@
+import request from 'supertest';
+import express from 'express';
+import jwt from 'jsonwebtoken';
+import { PrismaClient } from '@prisma/client';
+
+// A single in-memory room stands in for the database
+jest.mock('@prisma/client', () => {
+  const room = { findUnique: jest.fn(), updateMany: jest.fn(), findUniqueOrThrow: jest.fn() };
+  const client: any = { $use: jest.fn(), $connect: jest.fn().mockResolvedValue(undefined), room };
+  client.$transaction = jest.fn((fn: (tx: unknown) => unknown) => fn(client));
+  return { PrismaClient: jest.fn(() => client), Prisma: { DbNull: 'DbNull' } };
+});
+
+import roomRoutes from '../src/routes/rooms';
+
+const db = new (PrismaClient as any)();
+
+let stored: { id: string; name: string; settings: unknown; version: number };
+
+const app = express();
+app.use(express.json());
+app.use('/api/rooms', roomRoutes);
+
+process.env.ADMIN_AUTH_SECRET = 'test-secret';
+const adminToken = jwt.sign({ userId: 'admin', type: 'admin' }, 'test-secret');
+
+const patch = (ifMatch?: string) => {
+  const req = request(app)
+    .patch('/api/rooms/room-1')
+    .set('Authorization', `Bearer ${adminToken}`)
+    .set('Content-Type', 'application/merge-patch+json');
+  return (ifMatch ? req.set('If-Match', ifMatch) : req).send(JSON.stringify({ name: 'studio-b' }));
+};
+
+beforeEach(() => {
+  stored = { id: 'room-1', name: 'studio-a', settings: null, version: 1 };
+  db.room.findUnique.mockImplementation(async ({ where }: any) => (where.id === stored.id ? { ...stored } : null));
+  db.room.findUniqueOrThrow.mockImplementation(async () => ({ ...stored }));
+  db.room.updateMany.mockImplementation(async ({ where, data }: any) => {
+    if (where.id !== stored.id || where.version !== stored.version) {
+      return { count: 0 };
+    }
+    const { version, ...fields } = data;
+    stored = { ...stored, ...fields, version: stored.version + version.increment };
+    return { count: 1 };
+  });
+});
+
+describe('Room ETags and conditional updates', () => {
+  it('returns the version as an ETag on GET', async () => {
+    const res = await request(app).get('/api/rooms/room-1');
+
+    expect(res.status).toBe(200);
+    expect(res.headers.etag).toBe('"1"');
+  });
+
+  it('applies a PATCH with a fresh If-Match and bumps the version', async () => {
+    const res = await patch('"1"');
+
+    expect(res.status).toBe(200);
+    expect(res.body.data.room.name).toBe('studio-b');
+    expect(res.headers.etag).toBe('"2"');
+    expect(stored.version).toBe(2);
+  });
+
+  it('rejects a PATCH with a stale If-Match with 412', async () => {
+    stored.version = 2;
+
+    const res = await patch('"1"');
+
+    expect(res.status).toBe(412);
+    expect(stored.name).toBe('studio-a');
+  });
+
+  it('rejects a PATCH with a weak If-Match tag', async () => {
+    expect((await patch('W/"1"')).status).toBe(412);
+  });
+
+  it('requires If-Match on PATCH', async () => {
+    expect((await patch()).status).toBe(428);
+  });
+
+  it('requires a token on PATCH', async () => {
+    const res = await request(app)
+      .patch('/api/rooms/room-1')
+      .set('Content-Type', 'application/merge-patch+json')
+      .set('If-Match', '"1"')
+      .send(JSON.stringify({ name: 'studio-b' }));
+
+    expect(res.status).toBe(401);
+    expect(stored.name).toBe('studio-a');
+  });
+
+  describe('PATCH /rooms/:id/settings', () => {
+    const patchSettings = (ifMatch?: string) => {
+      const req = request(app)
+        .patch('/api/rooms/room-1/settings')
+        .set('Authorization', `Bearer ${adminToken}`);
+      return (ifMatch ? req.set('If-Match', ifMatch) : req).send({ bitrate: 4000 });
+    };
+
+    it('applies with a fresh If-Match and bumps the version', async () => {
+      const res = await patchSettings('"1"');
+
+      expect(res.status).toBe(200);
+      expect(res.body).toEqual({ bitrate: 4000 });
+      expect(res.headers.etag).toBe('"2"');
+      expect(stored.version).toBe(2);
+    });
+
+    it('rejects a stale If-Match with 412', async () => {
+      stored.version = 2;
+
+      const res = await patchSettings('"1"');
+
+      expect(res.status).toBe(412);
+      expect(stored.settings).toBeNull();
+    });
+
+    it('rejects a write that lost a race with 412', async () => {
+      // Another update commits between the read and the guarded write
+      db.room.updateMany.mockResolvedValueOnce({ count: 0 });
+
+      expect((await patchSettings('"1"')).status).toBe(412);
+    });
+
+    it('requires If-Match', async () => {
+      expect((await patchSettings()).status).toBe(428);
+    });
+  });
+});
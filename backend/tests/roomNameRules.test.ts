@@ -0,0 +1,36 @@
+import { assertRoomNameAllowed } from '../src/utils/roomNameRules';
+import { AppError } from '../src/middleware/errorHandler';
+
+const rules = { minNameLength: 2, reservedNames: ['admin', 'system', 'lobby'] };
+
+const rejectionOf = (name: string): AppError | undefined => {
+  try {
+    assertRoomNameAllowed(name, rules);
+    return undefined;
+  } catch (error) {
+    return error as AppError;
+  }
+};
+
+describe('Room name rules', () => {
+  it('rejects a reserved name with 422, ignoring case', () => {
+    for (const name of ['lobby', 'Lobby', 'ADMIN', ' system ']) {
+      const error = rejectionOf(name);
+      expect(error).toBeInstanceOf(AppError);
+      expect(error?.statusCode).toBe(422);
+      expect(error?.message).toMatch(/reserved/);
+    }
+  });
+
+  it('rejects a name shorter than the minimum with 422', () => {
+    const error = rejectionOf('a');
+    expect(error).toBeInstanceOf(AppError);
+    expect(error?.statusCode).toBe(422);
+    expect(error?.message).toBe('Room name must be at least 2 characters');
+  });
+
+  it('accepts names that merely contain a reserved word', () => {
+    expect(rejectionOf('admin-briefing')).toBeUndefined();
+    expect(rejectionOf('Main Lobby')).toBeUndefined();
+  });
+});
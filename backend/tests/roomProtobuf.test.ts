@@ -0,0 +1,62 @@
+import request from 'supertest';
+import express from 'express';
+import { PrismaClient } from '@prisma/client';
+
+jest.mock('@prisma/client', () => {
+  const room = { findUnique: jest.fn(), count: jest.fn(), findMany: jest.fn() };
+  const client: any = { $use: jest.fn(), $connect: jest.fn().mockResolvedValue(undefined), room };
+  return { PrismaClient: jest.fn(() => client), Prisma: { DbNull: 'DbNull' } };
+});
+
+import { encodeRoom, encodeRoomList } from '../src/utils/roomProtobuf';
+import roomRoutes from '../src/routes/rooms';
+
+const db = new (PrismaClient as any)();
+
+const app = express();
+app.use('/api/rooms', roomRoutes);
+
+// Collect the raw protobuf body instead of letting supertest guess a text encoding
+const binary = (res: any, callback: (error: Error | null, body: Buffer) => void) => {
+  const chunks: Buffer[] = [];
+  res.on('data', (chunk: Buffer) => chunks.push(chunk));
+  res.on('end', () => callback(null, Buffer.concat(chunks)));
+};
+
+describe('room protobuf encoding', () => {
+  it('writes strings as length-delimited fields and numbers as varints', () => {
+    const encoded = encodeRoom({ id: 'r1', presenterLink: null, version: 300, createdAt: new Date(1700000000000) });
+
+    expect(encoded.toString('hex')).toBe([
+      '0a027231', // 1: "r1"
+      '70ac02', // 14: 300
+      '7880d095ffbc31', // 15: 1700000000000
+    ].join(''));
+  });
+
+  it('nests each room in the list and adds the cursor', () => {
+    const room = encodeRoom({ id: 'r1' });
+
+    expect(encodeRoomList([{ id: 'r1' }], { missing: ['x'], nextCursor: 'r1' })).toEqual(Buffer.concat([
+      Buffer.from([0x0a, room.length]), room,
+      Buffer.from('120178', 'hex'),
+      Buffer.from('1a027231', 'hex'),
+    ]));
+  });
+
+  it('serves GET /rooms/:id as protobuf when asked, and JSON otherwise', async () => {
+    const stored = { id: 'r1', name: 'Studio', version: 2, settings: { codec: 'h264' } };
+    db.room.findUnique.mockResolvedValue(stored);
+
+    const res = await request(app).get('/api/rooms/r1').set('Accept', 'application/x-protobuf').buffer(true).parse(binary);
+
+    expect(res.status).toBe(200);
+    expect(res.headers['content-type']).toMatch(/^application\/x-protobuf/);
+    expect(res.headers.etag).toBeDefined();
+    expect(res.body).toEqual(encodeRoom(stored as any));
+
+    const json = await request(app).get('/api/rooms/r1');
+    expect(json.headers['content-type']).toMatch(/^application\/json/);
+    expect(json.body.name).toBe('Studio');
+  });
+});
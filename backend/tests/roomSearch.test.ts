@@ -0,0 +1,65 @@
+import request from 'supertest';
+import express from 'express';
+import { PrismaClient } from '@prisma/client';
+
+process.env.ROOM_SEARCH_FTS = 'true';
+
+jest.mock('../src/utils/logger', () => ({ logger: { warn: jest.fn(), info: jest.fn(), error: jest.fn(), debug: jest.fn() } }));
+
+// Raw SQL fragments are flattened to text so the test can check what reached the database
+jest.mock('@prisma/client', () => {
+  const text = (part: unknown): string => (typeof part === 'object' && part !== null && 'text' in part ? (part as { text: string }).text : '?');
+  const sql = (strings: TemplateStringsArray, ...values: unknown[]) => ({
+    text: strings.reduce((out, chunk, i) => out + (i > 0 ? text(values[i - 1]) : '') + chunk, ''),
+  });
+  const join = (parts: unknown[], separator = ',') => ({ text: parts.map(text).join(separator) });
+  const room = { count: jest.fn(), findMany: jest.fn() };
+  const client: any = { $use: jest.fn(), $connect: jest.fn().mockResolvedValue(undefined), $queryRaw: jest.fn(), room };
+  return {
+    PrismaClient: jest.fn(() => client),
+    Prisma: { DbNull: 'DbNull', sql, join, empty: { text: '' } },
+  };
+});
+
+import roomRoutes from '../src/routes/rooms';
+
+const db = new (PrismaClient as any)();
+
+const app = express();
+app.use('/api/rooms', roomRoutes);
+
+describe('GET /rooms?q= with full-text search', () => {
+  beforeEach(() => {
+    jest.clearAllMocks();
+    db.room.findMany.mockResolvedValue([{ id: 'b' }, { id: 'c' }]);
+  });
+
+  it('fetches only the requested page of matching ids', async () => {
+    db.$queryRaw.mockResolvedValueOnce([{ id: 'b' }, { id: 'c' }]).mockResolvedValueOnce([{ total: 40 }]);
+
+    const res = await request(app).get('/api/rooms?q=studio&since_id=a&limit=2');
+
+    expect(res.status).toBe(200);
+    expect(res.headers['x-total-count']).toBe('40');
+    expect(db.$queryRaw.mock.calls[0][0].text).toMatch(/"id" > \?\s+ORDER BY "id" ASC\s+LIMIT \?/);
+    expect(db.room.count).not.toHaveBeenCalled();
+    expect(db.room.findMany).toHaveBeenCalledWith(expect.objectContaining({
+      where: { id: { in: ['b', 'c'] } },
+      take: 2,
+    }));
+    expect(res.body.data.nextCursor).toBe('c');
+  });
+
+  it('falls back to a substring match when the full-text query fails', async () => {
+    db.$queryRaw.mockRejectedValue(new Error('relation "Room" has no index'));
+    db.room.count.mockResolvedValue(2);
+
+    const res = await request(app).get('/api/rooms?q=studio');
+
+    expect(res.status).toBe(200);
+    expect(res.headers['x-total-count']).toBe('2');
+    expect(db.room.findMany).toHaveBeenCalledWith(expect.objectContaining({
+      where: { AND: [{ name: { contains: 'studio', mode: 'insensitive' } }] },
+    }));
+  });
+});
@@ -0,0 +1,31 @@
+// Exercises the real database, so it only runs when DATABASE_URL points at one
+// (with the schema pushed). Modules are loaded lazily because importing
+// lib/prisma connects immediately.
+const describeWithDb = process.env.DATABASE_URL ? describe : describe.skip;
+
+describeWithDb('nextSequenceValue', () => {
+  let prisma: typeof import('../src/lib/prisma').default;
+  let nextSequenceValue: typeof import('../src/services/sequences').nextSequenceValue;
+  const sequenceName = `test-seq-${Date.now()}`;
+
+  beforeAll(async () => {
+    prisma = (await import('../src/lib/prisma')).default;
+    ({ nextSequenceValue } = await import('../src/services/sequences'));
+  });
+
+  afterAll(async () => {
+    await prisma.sequence.deleteMany({ where: { name: sequenceName } });
+    await prisma.$disconnect();
+  });
+
+  it('never hands out the same value to concurrent callers', async () => {
+    const callers = 50;
+    const values = await Promise.all(
+      Array.from({ length: callers }, () => nextSequenceValue(sequenceName))
+    );
+
+    expect(new Set(values).size).toBe(callers);
+    expect(Math.min(...values)).toBe(1);
+    expect(Math.max(...values)).toBe(callers);
+  });
+});
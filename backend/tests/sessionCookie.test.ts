@@ -0,0 +1,70 @@
+import request from 'supertest';
+import express from 'express';
+import { auth } from 'express-openid-connect';
+import { buildSessionCookie } from '../src/config/cookieConfig';
+
+// An app with the OIDC middleware where /login populates the session the way the
+// callback does after a successful login, so the session cookie is issued.
+const buildApp = (baseURL: string, cookie: ReturnType<typeof buildSessionCookie>) => {
+  const app = express();
+  app.use(auth({
+    authRequired: false,
+    secret: 'a-long-random-test-secret-value',
+    baseURL,
+    clientID: 'test-client',
+    issuerBaseURL: 'https://issuer.example.com',
+    session: { cookie },
+  }));
+  app.get('/login', (req, res) => {
+    (req as any).appSession.sub = 'admin';
+    res.json({ status: 'success' });
+  });
+  return app;
+};
+
+const sessionCookieHeader = (res: request.Response): string => {
+  const cookies = ([] as string[]).concat(res.headers['set-cookie'] || []);
+  const session = cookies.find(cookie => cookie.startsWith('appSession='));
+  expect(session).toBeDefined();
+  return session!;
+};
+
+describe('Session cookie attributes', () => {
+  it('sets HttpOnly, Secure, SameSite, Domain and Path on the login response', async () => {
+    const cookie = buildSessionCookie('https://live.example.com', {
+      sameSite: 'Strict',
+      domain: 'live.example.com',
+      path: '/api',
+    });
+    const res = await request(buildApp('https://live.example.com', cookie)).get('/login');
+
+    const header = sessionCookieHeader(res);
+    expect(header).toMatch(/; HttpOnly/i);
+    expect(header).toMatch(/; Secure/i);
+    expect(header).toMatch(/; SameSite=Strict/i);
+    expect(header).toMatch(/; Domain=live\.example\.com/i);
+    expect(header).toMatch(/; Path=\/api/i);
+  });
+
+  it('omits Secure automatically when served over plain http', async () => {
+    const cookie = buildSessionCookie('http://localhost:3000', { sameSite: 'Lax', path: '/' });
+    const res = await request(buildApp('http://localhost:3000', cookie)).get('/login');
+
+    const header = sessionCookieHeader(res);
+    expect(header).toMatch(/; HttpOnly/i);
+    expect(header).not.toMatch(/; Secure/i);
+    expect(header).toMatch(/; SameSite=Lax/i);
+  });
+
+  it('honours an explicit Secure override', () => {
+    expect(buildSessionCookie('http://localhost:3000', { sameSite: 'Lax', secure: true, path: '/' }).secure).toBe(true);
+    expect(buildSessionCookie('https://live.example.com', { sameSite: 'Lax', secure: false, path: '/' }).secure).toBe(false);
+  });
+
+  it('forces Secure when SameSite=None', () => {
+    jest.spyOn(console, 'warn').mockImplementation(() => undefined);
+    const cookie = buildSessionCookie('http://localhost:3000', { sameSite: 'None', secure: false, path: '/' });
+    expect(cookie.secure).toBe(true);
+    expect(cookie.httpOnly).toBe(true);
+  });
+});
@@ -0,0 +1,97 @@
+import request from 'supertest';
+import express from 'express';
+import jwt from 'jsonwebtoken';
+
+jest.mock('../src/utils/logger', () => ({ logger: { warn: jest.fn(), info: jest.fn(), error: jest.fn(), debug: jest.fn() } }));
+
+jest.mock('@prisma/client', () => {
+  const room = { findUnique: jest.fn(), updateMany: jest.fn() };
+  const client: any = { $use: jest.fn(), $connect: jest.fn().mockResolvedValue(undefined), room };
+  client.$transaction = jest.fn((fn: (tx: unknown) => unknown) => fn(client));
+  return { PrismaClient: jest.fn(() => client), Prisma: { DbNull: 'DbNull' } };
+});
+
+type Validator = typeof import('../src/services/settingsValidator');
+
+// The validator is built from the environment when its module loads, so each test loads a fresh copy
+const loadValidator = (env: Record<string, string>): Validator => {
+  Object.assign(process.env, env);
+  let loaded!: Validator;
+  jest.isolateModules(() => {
+    loaded = require('../src/services/settingsValidator');
+  });
+  return loaded;
+};
+
+const capabilitiesResponse = (body: unknown) => ({ ok: true, status: 200, json: async () => body }) as Response;
+
+describe('settingsValidator', () => {
+  const ENV_NAMES = ['ROOM_SETTINGS_ALLOWLIST', 'ROOM_SETTINGS_CAPABILITIES_URL', 'ROOM_SETTINGS_CAPABILITIES_TTL'];
+  const originalFetch = global.fetch;
+
+  afterEach(() => {
+    ENV_NAMES.forEach(name => delete process.env[name]);
+    global.fetch = originalFetch;
+    jest.restoreAllMocks();
+  });
+
+  it('accepts any settings when nothing is configured', async () => {
+    const { assertSettingsSupported } = loadValidator({});
+
+    await expect(assertSettingsSupported({ codec: 'av1' })).resolves.toBeUndefined();
+  });
+
+  it('rejects values outside the static allowlist with a 422 listing them', async () => {
+    const { assertSettingsSupported } = loadValidator({ ROOM_SETTINGS_ALLOWLIST: 'codec=h264|vp8,resolution=720p|1080p' });
+
+    await expect(assertSettingsSupported({ codec: 'H264', resolution: ['720p', '1080p'], region: 'eu' })).resolves.toBeUndefined();
+    await expect(assertSettingsSupported({ codec: 'av1', resolution: ['720p', '2160p'] })).rejects.toMatchObject({
+      statusCode: 422,
+      message: 'Unsupported settings: codec=av1, resolution=2160p',
+    });
+  });
+
+  it('caches the capabilities endpoint for ROOM_SETTINGS_CAPABILITIES_TTL', async () => {
+    const fetchMock = jest.fn().mockResolvedValue(capabilitiesResponse({ codec: ['h264', 'opus'] }));
+    global.fetch = fetchMock;
+    const { assertSettingsSupported } = loadValidator({
+      ROOM_SETTINGS_CAPABILITIES_URL: 'http://media.example/capabilities',
+      ROOM_SETTINGS_CAPABILITIES_TTL: '1m',
+    });
+    let now = 1_000_000;
+    jest.spyOn(Date, 'now').mockImplementation(() => now);
+
+    await expect(assertSettingsSupported({ codec: 'vp9' })).rejects.toThrow('Unsupported settings: codec=vp9');
+    await expect(assertSettingsSupported({ codec: 'opus' })).resolves.toBeUndefined();
+    expect(fetchMock).toHaveBeenCalledTimes(1);
+    expect(fetchMock).toHaveBeenCalledWith('http://media.example/capabilities', expect.anything());
+
+    // Once the cache expires, a failed lookup keeps the last known capabilities
+    now += 61_000;
+    fetchMock.mockRejectedValueOnce(new Error('connect ECONNREFUSED'));
+    await expect(assertSettingsSupported({ codec: 'vp9' })).rejects.toThrow('Unsupported settings: codec=vp9');
+    expect(fetchMock).toHaveBeenCalledTimes(2);
+  });
+
+  it('answers 422 from PATCH /rooms/:id/settings before looking up the room', async () => {
+    process.env.ROOM_SETTINGS_ALLOWLIST = 'codec=h264';
+    let roomRoutes!: express.Router;
+    jest.isolateModules(() => {
+      roomRoutes = require('../src/routes/rooms').default;
+    });
+    const app = express();
+    app.use(express.json());
+    app.use('/api/rooms', roomRoutes);
+    process.env.ADMIN_AUTH_SECRET = 'test-secret';
+
+    const res = await request(app)
+      .patch('/api/rooms/room-1/settings')
+      .set('Authorization', `Bearer ${jwt.sign({ userId: 'admin', type: 'admin' }, 'test-secret')}`)
+      .set('If-Match', '"1"')
+      .send({ codec: 'av1' });
+
+    // The mocked room lookup finds nothing, so reaching it would have answered 404
+    expect(res.status).toBe(422);
+    expect(res.body.error).toBe('Unsupported settings: codec=av1');
+  });
+});
@@ -136,8 +136,8 @@ const RoomManagement: React.FC = () => {
 
     setLoading(true);
     try {
-      await deleteRoom(roomId);
-      setSuccess('Room deleted successfully');
+      const result = await deleteRoom(roomId);
+      setSuccess(`Deleted '${result.data.room.name}'`);
       fetchRooms();
     } catch (error) {
       console.error('Error deleting room:', error);
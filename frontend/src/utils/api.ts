@@ -66,6 +66,8 @@ interface Room {
   streamKey: string;
   displayPassword: string;
   password: string;
+  availableFrom?: string | null;
+  availableUntil?: string | null;
 }
 
 export interface RoomConfig {
@@ -149,9 +151,9 @@ export const getRooms = async (): Promise<Room[]> => {
   return result.data.rooms;
 };
 
-export const deleteRoom = async (roomId: string): Promise<ApiResponse<null>> => {
+export const deleteRoom = async (roomId: string): Promise<ApiResponse<{ room: Room }>> => {
   const response = await api.delete(`/rooms/${roomId}`);
-  return response.data as ApiResponse<null>;
+  return response.data as ApiResponse<{ room: Room }>;
 };
 
 export const validateRoomAccess = async (roomId: string, password: string, isPresenter: boolean = false): Promise<RoomConfig> => {